@@ -0,0 +1,191 @@
+package alias
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// Aliases maps the names used by the players to their canonical identities
+type Aliases map[string]string
+
+// New creates the Aliases from the identities, each one with the names it is also known by
+func New(identities map[string][]string) (Aliases, error) {
+	a := Aliases{}
+
+	var names []string
+	for identity := range identities {
+		names = append(names, identity)
+	}
+	sort.Strings(names)
+
+	for _, identity := range names {
+		for _, name := range identities[identity] {
+			if name == identity {
+				continue
+			}
+			if _, ok := identities[name]; ok {
+				return nil, fmt.Errorf("%q is an identity and an alias of %q", name, identity)
+			}
+			if other, ok := a[name]; ok && other != identity {
+				return nil, fmt.Errorf("%q is an alias of %q and %q", name, other, identity)
+			}
+			a[name] = identity
+		}
+	}
+
+	return a, nil
+}
+
+// Load reads the identities from a json or yaml file, chosen by the file extension, like
+//
+//	{"Isgalamido": ["Isga", "[CLAN]Isgalamido"]}
+func Load(path string) (Aliases, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the aliases: %v", err)
+	}
+
+	identities := map[string][]string{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(b, &identities)
+	case ".yaml", ".yml":
+		identities, err = parseYAML(string(b))
+	default:
+		return nil, fmt.Errorf("unknown aliases format %q, should be .json, .yaml or .yml", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the aliases: %v", err)
+	}
+
+	return New(identities)
+}
+
+// Name returns the identity of the name, the names without alias are identities
+func (a Aliases) Name(name string) string {
+	if identity, ok := a[name]; ok {
+		return identity
+	}
+	return name
+}
+
+// Games applies the aliases to every game
+func (a Aliases) Games(gs []*parser.Game) []*parser.Game {
+	if len(a) == 0 {
+		return gs
+	}
+	games := make([]*parser.Game, len(gs))
+	for i, g := range gs {
+		games[i] = a.Game(g)
+	}
+	return games
+}
+
+// Game returns a copy of the game with the players named by their identities,
+// the results of the names of the same identity are summed
+func (a Aliases) Game(g *parser.Game) *parser.Game {
+	if len(a) == 0 || g == nil {
+		return g
+	}
+
+	c := *g
+
+	c.Players = []string{}
+	c.Kills = map[string]int{}
+	for _, p := range g.Players {
+		name := a.Name(p)
+		if _, ok := c.Kills[name]; !ok {
+			c.Players = append(c.Players, name)
+		}
+		c.Kills[name] += g.Kills[p]
+	}
+
+	c.TeamChanges = nil
+	for _, t := range g.TeamChanges {
+		tc := *t
+		tc.Player = a.Name(t.Player)
+		c.TeamChanges = append(c.TeamChanges, &tc)
+	}
+
+	c.FriendlyFire = a.sum(g.FriendlyFire)
+	c.PlayTime = a.sum(g.PlayTime)
+
+	c.CTF = nil
+	for p, s := range g.CTF {
+		if c.CTF == nil {
+			c.CTF = map[string]*parser.CTFStats{}
+		}
+		name := a.Name(p)
+		if _, ok := c.CTF[name]; !ok {
+			c.CTF[name] = &parser.CTFStats{}
+		}
+		c.CTF[name].Pickups += s.Pickups
+		c.CTF[name].Captures += s.Captures
+		c.CTF[name].Returns += s.Returns
+		c.CTF[name].CarrierKills += s.CarrierKills
+	}
+
+	c.Weapons = nil
+	for p, s := range g.Weapons {
+		if c.Weapons == nil {
+			c.Weapons = map[string]*parser.WeaponStats{}
+		}
+		name := a.Name(p)
+		if _, ok := c.Weapons[name]; !ok {
+			c.Weapons[name] = &parser.WeaponStats{}
+		}
+		w := c.Weapons[name]
+		w.Kills = merge(w.Kills, s.Kills)
+		w.Deaths = merge(w.Deaths, s.Deaths)
+	}
+
+	c.Bots = nil
+	for p, model := range g.Bots {
+		if c.Bots == nil {
+			c.Bots = map[string]string{}
+		}
+		c.Bots[a.Name(p)] = model
+	}
+
+	c.KillSequence = nil
+	for _, k := range g.KillSequence {
+		kill := *k
+		kill.Killer = a.Name(k.Killer)
+		kill.Dead = a.Name(k.Dead)
+		c.KillSequence = append(c.KillSequence, &kill)
+	}
+
+	return &c
+}
+
+// sum adds the values of the names of the same identity
+func (a Aliases) sum(m map[string]int) map[string]int {
+	if m == nil {
+		return nil
+	}
+	s := map[string]int{}
+	for name, v := range m {
+		s[a.Name(name)] += v
+	}
+	return s
+}
+
+// merge adds the values of src to dst, creating it when needed
+func merge(dst, src map[string]int) map[string]int {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = map[string]int{}
+	}
+	for k, v := range src {
+		dst[k] += v
+	}
+	return dst
+}
@@ -0,0 +1,180 @@
+package alias
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		name       string
+		identities map[string][]string
+		out        Aliases
+		err        bool
+	}{
+		{
+			name:       "aliases",
+			identities: map[string][]string{"Isgalamido": {"Isga", "[CLAN]Isgalamido", "Isgalamido"}, "Zeh": {}},
+			out:        Aliases{"Isga": "Isgalamido", "[CLAN]Isgalamido": "Isgalamido"},
+		},
+		{
+			name:       "alias of two identities",
+			identities: map[string][]string{"Isgalamido": {"Isga"}, "Zeh": {"Isga"}},
+			err:        true,
+		},
+		{
+			name:       "identity as alias",
+			identities: map[string][]string{"Isgalamido": {"Zeh"}, "Zeh": {}},
+			err:        true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := New(tc.identities)
+			if tc.err != (err != nil) {
+				t.Fatalf("was expecting error %v, but returns %v", tc.err, err)
+			}
+			if !tc.err && !reflect.DeepEqual(a, tc.out) {
+				t.Errorf("was expecting %v, but returns %v", tc.out, a)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aliases")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	out := Aliases{"Isga": "Isgalamido", "[CLAN]Isgalamido": "Isgalamido"}
+	testCases := []struct {
+		file    string
+		content string
+		out     Aliases
+		err     bool
+	}{
+		{
+			file:    "aliases.json",
+			content: `{"Isgalamido": ["Isga", "[CLAN]Isgalamido"]}`,
+			out:     out,
+		},
+		{
+			file:    "aliases.yaml",
+			content: "Isgalamido:\n  - Isga\n  - \"[CLAN]Isgalamido\"\n",
+			out:     out,
+		},
+		{
+			file:    "aliases.yml",
+			content: "Isgalamido: [Isga, \"[CLAN]Isgalamido\"]\n",
+			out:     out,
+		},
+		{
+			file:    "invalid.json",
+			content: `{"Isgalamido": "Isga"}`,
+			err:     true,
+		},
+		{
+			file:    "aliases.toml",
+			content: `Isgalamido = ["Isga"]`,
+			err:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.file, func(t *testing.T) {
+			path := filepath.Join(dir, tc.file)
+			if err := ioutil.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			a, err := Load(path)
+			if tc.err != (err != nil) {
+				t.Fatalf("was expecting error %v, but returns %v", tc.err, err)
+			}
+			if !tc.err && !reflect.DeepEqual(a, tc.out) {
+				t.Errorf("was expecting %v, but returns %v", tc.out, a)
+			}
+		})
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("was expecting an error for a missing file")
+	}
+}
+
+func TestAliasesGame(t *testing.T) {
+	a := Aliases{"Isga": "Isgalamido", "[CLAN]Isgalamido": "Isgalamido"}
+
+	in := &parser.Game{
+		ID:         "1",
+		GameType:   parser.GameTypeCTF,
+		TotalKills: 4,
+		Players:    []string{"Isga", "Mocinha", "[CLAN]Isgalamido"},
+		Kills:      map[string]int{"Isga": 2, "Mocinha": 1, "[CLAN]Isgalamido": 1},
+		TeamChanges: []*parser.TeamChange{
+			{Player: "Isga", Team: parser.TeamRed, Time: "0:01"},
+		},
+		FriendlyFire: map[string]int{"Isga": 1, "[CLAN]Isgalamido": 1},
+		CTF: map[string]*parser.CTFStats{
+			"Isga":             {Pickups: 1, Captures: 1},
+			"[CLAN]Isgalamido": {Pickups: 1, Returns: 1},
+		},
+		Weapons: map[string]*parser.WeaponStats{
+			"Isga":             {Kills: map[string]int{"MOD_ROCKET": 2}},
+			"[CLAN]Isgalamido": {Kills: map[string]int{"MOD_ROCKET": 1}, Deaths: map[string]int{"MOD_RAILGUN": 1}},
+		},
+		KillSequence: []*parser.Kill{
+			{Killer: "Mocinha", Dead: "Isga", Means: "MOD_RAILGUN", Time: "0:10"},
+		},
+		Bots:     map[string]string{"Isga": "sarge"},
+		PlayTime: map[string]int{"Isga": 60, "Mocinha": 120, "[CLAN]Isgalamido": 30},
+	}
+
+	out := &parser.Game{
+		ID:         "1",
+		GameType:   parser.GameTypeCTF,
+		TotalKills: 4,
+		Players:    []string{"Isgalamido", "Mocinha"},
+		Kills:      map[string]int{"Isgalamido": 3, "Mocinha": 1},
+		TeamChanges: []*parser.TeamChange{
+			{Player: "Isgalamido", Team: parser.TeamRed, Time: "0:01"},
+		},
+		FriendlyFire: map[string]int{"Isgalamido": 2},
+		CTF: map[string]*parser.CTFStats{
+			"Isgalamido": {Pickups: 2, Captures: 1, Returns: 1},
+		},
+		Weapons: map[string]*parser.WeaponStats{
+			"Isgalamido": {Kills: map[string]int{"MOD_ROCKET": 3}, Deaths: map[string]int{"MOD_RAILGUN": 1}},
+		},
+		KillSequence: []*parser.Kill{
+			{Killer: "Mocinha", Dead: "Isgalamido", Means: "MOD_RAILGUN", Time: "0:10"},
+		},
+		Bots:     map[string]string{"Isgalamido": "sarge"},
+		PlayTime: map[string]int{"Isgalamido": 90, "Mocinha": 120},
+	}
+
+	if g := a.Game(in); !reflect.DeepEqual(g, out) {
+		t.Errorf("was expecting %+v, but returns %+v", out, g)
+	}
+
+	// the original game is not changed
+	if in.Players[0] != "Isga" || in.Kills["Isga"] != 2 || in.KillSequence[0].Dead != "Isga" || in.Weapons["Isga"].Kills["MOD_ROCKET"] != 2 || in.Bots["Isga"] != "sarge" {
+		t.Errorf("was expecting the original game unchanged, but returns %+v", in)
+	}
+
+	if gs := a.Games([]*parser.Game{in}); !reflect.DeepEqual(gs, []*parser.Game{out}) {
+		t.Errorf("was expecting %+v, but returns %+v", out, gs[0])
+	}
+
+	if g := (Aliases{}).Game(in); g != in {
+		t.Errorf("was expecting the same game without aliases, but returns %+v", g)
+	}
+}
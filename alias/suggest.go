@@ -0,0 +1,189 @@
+package alias
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// DefaultDistance is the edit distance used to suggest the aliases
+const DefaultDistance = 2
+
+// the reasons of the suggestions
+const (
+	ReasonDistance = "distance"
+	ReasonClient   = "client"
+)
+
+var (
+	// colorCodes matches the quake color codes, like ^1
+	colorCodes = regexp.MustCompile(`\^[0-9a-zA-Z]`)
+	// clanTags matches the clan tags, like [CLAN] or (CLAN)
+	clanTags = regexp.MustCompile(`\[[^\]]*\]|\([^)]*\)|\{[^}]*\}`)
+)
+
+// Suggestion represents a name which looks like an alias of an identity
+type Suggestion struct {
+	Identity string `json:"identity"`
+	Alias    string `json:"alias"`
+	Reason   string `json:"reason"`
+}
+
+// normalize removes from the name what usually changes between the aliases,
+// the color codes, the clan tags, the case and the spaces
+func normalize(name string) string {
+	name = colorCodes.ReplaceAllString(name, "")
+	name = clanTags.ReplaceAllString(name, "")
+	return strings.ToLower(strings.Join(strings.Fields(name), ""))
+}
+
+// distance returns the levenshtein distance between a and b
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func min(vs ...int) int {
+	m := vs[0]
+	for _, v := range vs[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// prefix reports if the shorter normalized name starts the longer one, like isga and isgalamido
+func prefix(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	return len(a) >= 3 && strings.HasPrefix(b, a)
+}
+
+// Suggest returns the names which look like the same player, the names are similar when
+// their normalized names are at most maxDistance edits apart or one starts the other,
+// or when the same client renamed between them, the identity of a group is the name
+// seen in more games
+func Suggest(games []*parser.Game, renames []*parser.Event, maxDistance int) []*Suggestion {
+	seen := map[string]int{}
+	var names []string
+	for _, g := range games {
+		for _, p := range g.Players {
+			if _, ok := seen[p]; !ok {
+				names = append(names, p)
+			}
+			seen[p]++
+		}
+	}
+	for _, e := range renames {
+		for _, p := range []string{e.Previous, e.Player} {
+			if _, ok := seen[p]; !ok && p != "" {
+				names = append(names, p)
+				seen[p] = 0
+			}
+		}
+	}
+	sort.Strings(names)
+
+	parent := map[string]string{}
+	var find func(string) string
+	find = func(n string) string {
+		if p, ok := parent[n]; ok && p != n {
+			parent[n] = find(p)
+			return parent[n]
+		}
+		return n
+	}
+	reasons := map[string]string{}
+	union := func(a, b, reason string) {
+		ra, rb := find(a), find(b)
+		if ra != rb {
+			parent[rb] = ra
+		}
+		for _, n := range []string{a, b} {
+			if _, ok := reasons[n]; !ok || reason == ReasonClient {
+				reasons[n] = reason
+			}
+		}
+	}
+
+	for _, e := range renames {
+		if e.Type == parser.EventRename && e.Previous != "" && e.Player != "" && e.Previous != e.Player {
+			union(e.Previous, e.Player, ReasonClient)
+		}
+	}
+
+	for i, a := range names {
+		na := normalize(a)
+		for _, b := range names[i+1:] {
+			nb := normalize(b)
+			if na == "" || nb == "" {
+				continue
+			}
+			if distance(na, nb) <= maxDistance || prefix(na, nb) {
+				union(a, b, ReasonDistance)
+			}
+		}
+	}
+
+	groups := map[string][]string{}
+	for _, n := range names {
+		r := find(n)
+		groups[r] = append(groups[r], n)
+	}
+
+	var suggestions []*Suggestion
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		identity := group[0]
+		for _, n := range group[1:] {
+			if seen[n] > seen[identity] {
+				identity = n
+			}
+		}
+		for _, n := range group {
+			if n != identity {
+				suggestions = append(suggestions, &Suggestion{Identity: identity, Alias: n, Reason: reasons[n]})
+			}
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Identity != suggestions[j].Identity {
+			return suggestions[i].Identity < suggestions[j].Identity
+		}
+		return suggestions[i].Alias < suggestions[j].Alias
+	})
+
+	return suggestions
+}
+
+// Identities groups the suggestions by identity, in the format of the aliases file
+func Identities(suggestions []*Suggestion) map[string][]string {
+	identities := map[string][]string{}
+	for _, s := range suggestions {
+		identities[s.Identity] = append(identities[s.Identity], s.Alias)
+	}
+	return identities
+}
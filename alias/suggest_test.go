@@ -0,0 +1,75 @@
+package alias
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		in  string
+		out string
+	}{
+		{in: "Isgalamido", out: "isgalamido"},
+		{in: "^1Isga^7lamido", out: "isgalamido"},
+		{in: "[CLAN] Isgalamido", out: "isgalamido"},
+		{in: "Dono da Bola", out: "donodabola"},
+	}
+
+	for _, tc := range testCases {
+		if out := normalize(tc.in); out != tc.out {
+			t.Errorf("was expecting %q, but returns %q", tc.out, out)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		out  int
+	}{
+		{a: "", b: "abc", out: 3},
+		{a: "zeh", b: "zeh", out: 0},
+		{a: "zeh", b: "zé", out: 2},
+		{a: "kitten", b: "sitting", out: 3},
+	}
+
+	for _, tc := range testCases {
+		if out := distance(tc.a, tc.b); out != tc.out {
+			t.Errorf("was expecting %d for %q and %q, but returns %d", tc.out, tc.a, tc.b, out)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	games := []*parser.Game{
+		{ID: "1", Players: []string{"Isgalamido", "Mocinha", "Zeh"}},
+		{ID: "2", Players: []string{"Isga", "Mocinha", "Dono da Bola"}},
+		{ID: "3", Players: []string{"[CLAN]Isgalamido", "Isgalamido", "Zeh"}},
+	}
+	renames := []*parser.Event{
+		{Type: parser.EventRename, Previous: "Dono da Bola", Player: "Bola"},
+		{Type: parser.EventRename, Previous: "Zeh", Player: "Zeh"},
+	}
+
+	out := []*Suggestion{
+		{Identity: "Dono da Bola", Alias: "Bola", Reason: ReasonClient},
+		{Identity: "Isgalamido", Alias: "Isga", Reason: ReasonDistance},
+		{Identity: "Isgalamido", Alias: "[CLAN]Isgalamido", Reason: ReasonDistance},
+	}
+
+	s := Suggest(games, renames, DefaultDistance)
+	if !reflect.DeepEqual(s, out) {
+		t.Errorf("was expecting %+v, but returns %+v", out, s)
+	}
+
+	identities := map[string][]string{
+		"Dono da Bola": {"Bola"},
+		"Isgalamido":   {"Isga", "[CLAN]Isgalamido"},
+	}
+	if i := Identities(s); !reflect.DeepEqual(i, identities) {
+		t.Errorf("was expecting %v, but returns %v", identities, i)
+	}
+}
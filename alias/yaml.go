@@ -0,0 +1,160 @@
+package alias
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseYAML reads the identities from the subset of yaml used by the aliases file,
+// a mapping of identities to lists of names, in the block or flow style, like
+//
+//	Isgalamido:
+//	  - Isga
+//	  - "[CLAN]Isgalamido"
+//	Zeh: [Zé, "Zeh "]
+//
+// the comments starting with # are ignored
+func parseYAML(text string) (map[string][]string, error) {
+	identities := map[string][]string{}
+	identity := ""
+
+	for i, line := range strings.Split(text, "\n") {
+		n := i + 1
+		line = strings.TrimRight(stripComment(line), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		// the list items belong to the last identity
+		if strings.HasPrefix(trimmed, "-") {
+			if identity == "" || line == trimmed {
+				return nil, fmt.Errorf("line %d: list item without identity", n)
+			}
+			name, err := unquote(strings.TrimSpace(strings.TrimPrefix(trimmed, "-")))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", n, err)
+			}
+			identities[identity] = append(identities[identity], name)
+			continue
+		}
+
+		if line != trimmed {
+			return nil, fmt.Errorf("line %d: unexpected indentation", n)
+		}
+
+		key, value, err := splitKey(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		if _, ok := identities[key]; ok {
+			return nil, fmt.Errorf("line %d: duplicated identity %q", n, key)
+		}
+		identity = key
+		identities[identity] = []string{}
+
+		if value == "" {
+			continue
+		}
+		if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+			return nil, fmt.Errorf("line %d: the names of %q should be a list", n, key)
+		}
+		for _, item := range splitFlow(value[1 : len(value)-1]) {
+			name, err := unquote(strings.TrimSpace(item))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", n, err)
+			}
+			identities[identity] = append(identities[identity], name)
+		}
+		identity = ""
+	}
+
+	return identities, nil
+}
+
+// stripComment removes the comment of the line, ignoring the # inside quotes
+func stripComment(line string) string {
+	quote := rune(0)
+	for i, c := range line {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// splitKey separates a "key: value" line, the key can be quoted
+func splitKey(line string) (key, value string, err error) {
+	end := 0
+	if line[0] == '"' || line[0] == '\'' {
+		end = strings.IndexByte(line[1:], line[0]) + 2
+		if end < 2 {
+			return "", "", fmt.Errorf("unterminated quote")
+		}
+	}
+	i := strings.Index(line[end:], ":")
+	if i < 0 {
+		return "", "", fmt.Errorf("expected \"identity:\"")
+	}
+	key, err = unquote(strings.TrimSpace(line[:end+i]))
+	if err != nil {
+		return "", "", err
+	}
+	if key == "" {
+		return "", "", fmt.Errorf("empty identity")
+	}
+	return key, strings.TrimSpace(line[end+i+1:]), nil
+}
+
+// splitFlow separates the items of a flow list, ignoring the commas inside quotes
+func splitFlow(text string) []string {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	var items []string
+	quote := rune(0)
+	start := 0
+	for i, c := range text {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, text[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, text[start:])
+}
+
+// unquote removes the quotes of a scalar, the double quoted ones can have escapes
+func unquote(s string) (string, error) {
+	if s == "" {
+		return "", fmt.Errorf("empty name")
+	}
+	switch s[0] {
+	case '"':
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid quoted name %s", s)
+		}
+		return v, nil
+	case '\'':
+		if len(s) < 2 || s[len(s)-1] != '\'' {
+			return "", fmt.Errorf("invalid quoted name %s", s)
+		}
+		return strings.Replace(s[1:len(s)-1], "''", "'", -1), nil
+	}
+	return s, nil
+}
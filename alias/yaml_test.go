@@ -0,0 +1,73 @@
+package alias
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   string
+		out  map[string][]string
+		err  bool
+	}{
+		{
+			name: "block",
+			in:   "# players\nIsgalamido:\n  - Isga # short\n  - \"[CLAN]Isgalamido\"\n\nZeh:\n  - 'Zeh''s'\n",
+			out:  map[string][]string{"Isgalamido": {"Isga", "[CLAN]Isgalamido"}, "Zeh": {"Zeh's"}},
+		},
+		{
+			name: "flow",
+			in:   "Isgalamido: [Isga, \"Isga, the one\"]\n\"Dono da Bola\": []\n",
+			out:  map[string][]string{"Isgalamido": {"Isga", "Isga, the one"}, "Dono da Bola": {}},
+		},
+		{
+			name: "comment inside quotes",
+			in:   "Mal: [\"#1 Mal\"]",
+			out:  map[string][]string{"Mal": {"#1 Mal"}},
+		},
+		{
+			name: "item without identity",
+			in:   "  - Isga\n",
+			err:  true,
+		},
+		{
+			name: "item after flow list",
+			in:   "Isgalamido: [Isga]\n  - Isgalamido2\n",
+			err:  true,
+		},
+		{
+			name: "scalar names",
+			in:   "Isgalamido: Isga\n",
+			err:  true,
+		},
+		{
+			name: "duplicated identity",
+			in:   "Isgalamido: [Isga]\nIsgalamido: [Isgala]\n",
+			err:  true,
+		},
+		{
+			name: "missing colon",
+			in:   "Isgalamido\n",
+			err:  true,
+		},
+		{
+			name: "indented identity",
+			in:   "Isgalamido:\n  Isga:\n",
+			err:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := parseYAML(tc.in)
+			if tc.err != (err != nil) {
+				t.Fatalf("was expecting error %v, but returns %v", tc.err, err)
+			}
+			if !tc.err && !reflect.DeepEqual(out, tc.out) {
+				t.Errorf("was expecting %v, but returns %v", tc.out, out)
+			}
+		})
+	}
+}
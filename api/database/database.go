@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+
+	"github.com/bgildson/enext-challenge/schema"
 )
 
 // Database indicates how to implements a Database
@@ -21,6 +23,7 @@ var (
 )
 
 type jsonDatabase struct {
+	ids  []string
 	data map[string]map[string]interface{}
 }
 
@@ -33,19 +36,39 @@ func NewJSONDatabase(gamesJSONPath string) (Database, error) {
 		return nil, ErrDatabaseFileNotFound
 	}
 
-	var data map[string]map[string]interface{}
-	err = json.Unmarshal(b, &data)
+	// accepts every schema version of the games json
+	doc, err := schema.Decode(b)
 	if err != nil {
 		return nil, ErrCouldNotDeserializeDatabaseContent
 	}
 
-	return &jsonDatabase{data}, nil
+	d := &jsonDatabase{
+		data: map[string]map[string]interface{}{},
+	}
+	for _, g := range doc.Games {
+		gb, err := json.Marshal(g)
+		if err != nil {
+			return nil, ErrCouldNotDeserializeDatabaseContent
+		}
+
+		var m map[string]interface{}
+		if err := json.Unmarshal(gb, &m); err != nil {
+			return nil, ErrCouldNotDeserializeDatabaseContent
+		}
+
+		if _, ok := d.data[g.ID]; !ok {
+			d.ids = append(d.ids, g.ID)
+		}
+		d.data[g.ID] = m
+	}
+
+	return d, nil
 }
 
 func (d *jsonDatabase) Get() ([]map[string]interface{}, error) {
 	var r []map[string]interface{}
-	for _, v := range d.data {
-		r = append(r, v)
+	for _, id := range d.ids {
+		r = append(r, d.data[id])
 	}
 	return r, nil
 }
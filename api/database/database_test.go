@@ -25,6 +25,11 @@ func TestNewJSONDatabase(t *testing.T) {
 			in:          path.Join(fixturesPath, "games.json"),
 			out:         nil,
 		},
+		{
+			description: "success create and load versioned database data",
+			in:          path.Join(fixturesPath, "games_v2.json"),
+			out:         nil,
+		},
 		{
 			description: "problems when looking for the database file",
 			in:          path.Join(fixturesPath, "games_nonexistent.json"),
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrDatabaseNotLoaded is returned by the queries while the database is still being loaded
+var ErrDatabaseNotLoaded = errors.New("the database was not loaded yet")
+
+type loadingDatabase struct {
+	mu sync.RWMutex
+	db Database
+}
+
+// NewLoadingJSONDatabase creates a new Database for JSON source which is loaded in background,
+// retrying every interval until the file can be loaded or the context is done,
+// meanwhile the queries fail with ErrDatabaseNotLoaded
+func NewLoadingJSONDatabase(ctx context.Context, gamesJSONPath string, interval time.Duration) Database {
+	d := &loadingDatabase{}
+	go d.load(ctx, gamesJSONPath, interval)
+	return d
+}
+
+func (d *loadingDatabase) load(ctx context.Context, gamesJSONPath string, interval time.Duration) {
+	var last error
+	for {
+		db, err := NewJSONDatabase(gamesJSONPath)
+		if err == nil {
+			d.mu.Lock()
+			d.db = db
+			d.mu.Unlock()
+			return
+		}
+
+		// the same failure is logged only once
+		if last == nil || err.Error() != last.Error() {
+			log.Printf("could not load the database, retrying every %v: %v", interval, err)
+		}
+		last = err
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// loaded returns the loaded database, or nil while it is not loaded
+func (d *loadingDatabase) loaded() Database {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.db
+}
+
+func (d *loadingDatabase) Get() ([]map[string]interface{}, error) {
+	db := d.loaded()
+	if db == nil {
+		return nil, ErrDatabaseNotLoaded
+	}
+	return db.Get()
+}
+
+func (d *loadingDatabase) GetByID(id string) (map[string]interface{}, error) {
+	db := d.loaded()
+	if db == nil {
+		return nil, ErrDatabaseNotLoaded
+	}
+	return db.GetByID(id)
+}
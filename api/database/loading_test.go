@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadingJSONDatabase(t *testing.T) {
+	basePath, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("could not determine where the app is running: %v", err)
+	}
+	games, err := ioutil.ReadFile(path.Join(basePath, "..", "..", "fixtures", "games.json"))
+	if err != nil {
+		t.Fatalf("could not read the fixture: %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "database")
+	if err != nil {
+		t.Fatalf("could not create the database directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	gamesJSONPath := filepath.Join(dir, "games.json")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d := NewLoadingJSONDatabase(ctx, gamesJSONPath, 10*time.Millisecond)
+
+	// the file does not exist yet
+	if _, err := d.Get(); err != ErrDatabaseNotLoaded {
+		t.Errorf("was expecting %v, but returns %v", ErrDatabaseNotLoaded, err)
+	}
+	if _, err := d.GetByID("1"); err != ErrDatabaseNotLoaded {
+		t.Errorf("was expecting %v, but returns %v", ErrDatabaseNotLoaded, err)
+	}
+
+	if err := ioutil.WriteFile(gamesJSONPath, games, 0644); err != nil {
+		t.Fatalf("could not write the database: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		gs, err := d.Get()
+		if err == nil {
+			if len(gs) == 0 {
+				t.Errorf("was expecting the games of the database, but returns none")
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("was expecting the database to be loaded, but returns %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, err := d.GetByID("1"); err != nil {
+		t.Errorf("an unexpected error occurred: %v", err)
+	}
+}
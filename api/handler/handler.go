@@ -1,11 +1,17 @@
 package handler
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/bgildson/enext-challenge/api/serializer"
 	"github.com/bgildson/enext-challenge/api/service"
 	"github.com/bgildson/enext-challenge/api/util"
+	"github.com/bgildson/enext-challenge/parser"
+	"github.com/bgildson/enext-challenge/report"
 	"github.com/go-chi/chi"
 )
 
@@ -13,6 +19,8 @@ import (
 type GamesHandler interface {
 	GetOne(http.ResponseWriter, *http.Request)
 	GetAll(http.ResponseWriter, *http.Request)
+	GetAchievements(http.ResponseWriter, *http.Request)
+	GetTimeline(http.ResponseWriter, *http.Request)
 }
 
 type gamesHandler struct {
@@ -25,45 +33,179 @@ func NewGamesHandler(service service.GamesService) GamesHandler {
 }
 
 func (h *gamesHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	f, err := negotiate(r)
+	if err != nil {
+		handleFailure(w, http.StatusNotAcceptable, err)
+		return
+	}
+
 	games, err := h.service.List()
 	if err != nil {
 		handleFailure(w, http.StatusBadGateway, err)
 		return
 	}
 
-	s := serializer.NewJSONGamesSerializer()
-
-	b, err := s.Serialize(games)
+	b, err := f.Games.Serialize(games)
 	if err != nil {
 		handleFailure(w, http.StatusBadGateway, err)
 		return
 	}
 
-	handleSuccess(w, http.StatusOK, b)
+	handleSuccess(w, http.StatusOK, f.ContentType, b)
 }
 
 func (h *gamesHandler) GetOne(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
+	f, err := negotiate(r)
+	if err != nil {
+		handleFailure(w, http.StatusNotAcceptable, err)
+		return
+	}
+
+	game, err := h.service.Find(id)
+	if err != nil {
+		handleFailure(w, http.StatusBadGateway, err)
+		return
+	}
+
+	b, err := f.Game.Serialize(game)
+	if err != nil {
+		handleFailure(w, http.StatusBadGateway, err)
+		return
+	}
+
+	handleSuccess(w, http.StatusOK, f.ContentType, b)
+}
+
+// GetAchievements replies the awards of a game, the streak, multi_kill and window
+// query params change the default config, like ?streak=3&window=5s
+func (h *gamesHandler) GetAchievements(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	f, err := negotiateJSON(r)
+	if err != nil {
+		handleFailure(w, http.StatusNotAcceptable, err)
+		return
+	}
+
+	c, err := achievementConfig(r)
+	if err != nil {
+		handleFailure(w, http.StatusBadRequest, err)
+		return
+	}
+
 	game, err := h.service.Find(id)
 	if err != nil {
 		handleFailure(w, http.StatusBadGateway, err)
 		return
 	}
 
-	s := serializer.NewJSONGameSerializer()
+	awards := report.Achievements(game, c)
+	if awards == nil {
+		awards = []*report.Award{}
+	}
 
-	b, err := s.Serialize(game)
+	b, err := json.Marshal(awards)
 	if err != nil {
 		handleFailure(w, http.StatusBadGateway, err)
 		return
 	}
 
-	handleSuccess(w, http.StatusOK, b)
+	handleSuccess(w, http.StatusOK, f.ContentType, b)
 }
 
-func handleSuccess(w http.ResponseWriter, statusCode int, body []byte) {
-	w.Header().Set("Content-Type", "application/json")
+// gameTimeline represents the timeline of a game
+type gameTimeline struct {
+	ID       string                  `json:"id"`
+	Players  []string                `json:"players"`
+	Timeline []*report.TimelinePoint `json:"timeline"`
+}
+
+// GetTimeline replies the kills of a game in the order they happened, with the scores after each one,
+// as json or, for charting, as csv
+func (h *gamesHandler) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	f, err := negotiate(r)
+	if err == nil && f.Name != "json" && f.Name != "csv" {
+		err = serializer.ErrNotAcceptable
+	}
+	if err != nil {
+		handleFailure(w, http.StatusNotAcceptable, err)
+		return
+	}
+
+	game, err := h.service.Find(id)
+	if err != nil {
+		handleFailure(w, http.StatusBadGateway, err)
+		return
+	}
+
+	var b []byte
+	if f.Name == "csv" {
+		b, err = report.TimelineCSV([]*parser.Game{game})
+	} else {
+		timeline := report.Timeline(game)
+		if timeline == nil {
+			timeline = []*report.TimelinePoint{}
+		}
+		b, err = json.Marshal(&gameTimeline{ID: game.ID, Players: report.TimelinePlayers(game), Timeline: timeline})
+	}
+	if err != nil {
+		handleFailure(w, http.StatusBadGateway, err)
+		return
+	}
+
+	handleSuccess(w, http.StatusOK, f.ContentType, b)
+}
+
+// achievementConfig reads the achievement config from the query params
+func achievementConfig(r *http.Request) (report.AchievementConfig, error) {
+	c := report.DefaultAchievementConfig
+	q := r.URL.Query()
+
+	if v := q.Get("streak"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return c, fmt.Errorf("invalid streak %q", v)
+		}
+		c.Streak = n
+	}
+	if v := q.Get("multi_kill"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 2 {
+			return c, fmt.Errorf("invalid multi_kill %q", v)
+		}
+		c.MultiKill = n
+	}
+	if v := q.Get("window"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return c, fmt.Errorf("invalid window %q", v)
+		}
+		c.Window = d
+	}
+
+	return c, nil
+}
+
+// negotiate chooses the response format using the Accept header or the format query param
+func negotiate(r *http.Request) (*serializer.Format, error) {
+	return serializer.Negotiate(r.Header.Get("Accept"), r.URL.Query().Get("format"))
+}
+
+// negotiateJSON chooses the response format like negotiate, for the responses only serialized as json
+func negotiateJSON(r *http.Request) (*serializer.Format, error) {
+	f, err := negotiate(r)
+	if err == nil && f.Name != "json" {
+		err = serializer.ErrNotAcceptable
+	}
+	return f, err
+}
+
+func handleSuccess(w http.ResponseWriter, statusCode int, contentType string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(statusCode)
 	w.Write(body)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/bgildson/enext-challenge/api/service"
 	"github.com/bgildson/enext-challenge/api/util"
 	"github.com/bgildson/enext-challenge/parser"
+	"github.com/go-chi/chi"
 )
 
 func TestGamesHandler(t *testing.T) {
@@ -187,4 +188,240 @@ func TestGamesHandler(t *testing.T) {
 			}
 		})
 	})
+	t.Run("negotiation", func(t *testing.T) {
+		tt := []struct {
+			description string
+			accept      string
+			url         string
+			statusCode  int
+			contentType string
+		}{
+			{"default format", "", "/games", http.StatusOK, "application/json"},
+			{"csv by accept", "text/csv", "/games", http.StatusOK, "text/csv"},
+			{"xml by format", "application/json", "/games?format=xml", http.StatusOK, "application/xml"},
+			{"msgpack by accept", "application/msgpack", "/games", http.StatusOK, "application/msgpack"},
+			{"unsupported accept", "text/html", "/games", http.StatusNotAcceptable, "application/json"},
+			{"unsupported format", "", "/games?format=yaml", http.StatusNotAcceptable, "application/json"},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.description, func(t *testing.T) {
+				for _, handle := range []http.HandlerFunc{handlerSuccess.GetAll, handlerSuccess.GetOne} {
+					req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+					if err != nil {
+						t.Errorf("an unexpected error occurred: %v", err)
+					}
+					req.Header.Set("Accept", tc.accept)
+
+					rec := httptest.NewRecorder()
+
+					handle(rec, req)
+
+					if rec.Result().StatusCode != tc.statusCode {
+						t.Errorf(
+							"was expecting %d status code, but returns %d",
+							tc.statusCode,
+							rec.Result().StatusCode,
+						)
+					}
+
+					if ct := rec.Result().Header.Get("Content-Type"); ct != tc.contentType {
+						t.Errorf("was expecting %s content type, but returns %s", tc.contentType, ct)
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestGamesHandlerGetAchievements(t *testing.T) {
+	serviceSuccess := service.NewMockGamesService(
+		nil,
+		func(id string) (*parser.Game, error) {
+			return &parser.Game{
+				ID: id,
+				KillSequence: []*parser.Kill{
+					{Killer: "Isgalamido", Dead: "Mocinha", Time: "0:10"},
+					{Killer: "Isgalamido", Dead: "Zeh", Time: "0:14"},
+				},
+			}, nil
+		},
+	)
+	serviceFailure := service.NewMockGamesService(
+		nil,
+		func(id string) (*parser.Game, error) {
+			return nil, fmt.Errorf("could not get game %s", id)
+		},
+	)
+
+	tt := []struct {
+		description string
+		handler     GamesHandler
+		url         string
+		statusCode  int
+		body        string
+	}{
+		{
+			description: "with the default config",
+			handler:     NewGamesHandler(serviceSuccess),
+			url:         "/games/1/achievements",
+			statusCode:  http.StatusOK,
+			body:        `[{"type":"first_blood","player":"Isgalamido","kills":1,"time":"0:10"}]`,
+		},
+		{
+			description: "with a longer window",
+			handler:     NewGamesHandler(serviceSuccess),
+			url:         "/games/1/achievements?window=5s",
+			statusCode:  http.StatusOK,
+			body: `[{"type":"first_blood","player":"Isgalamido","kills":1,"time":"0:10"},` +
+				`{"type":"multi_kill","player":"Isgalamido","kills":2,"time":"0:10"}]`,
+		},
+		{
+			description: "with an invalid streak",
+			handler:     NewGamesHandler(serviceSuccess),
+			url:         "/games/1/achievements?streak=none",
+			statusCode:  http.StatusBadRequest,
+			body:        `{"message":"invalid streak \"none\""}`,
+		},
+		{
+			description: "unsupported format",
+			handler:     NewGamesHandler(serviceSuccess),
+			url:         "/games/1/achievements?format=csv",
+			statusCode:  http.StatusNotAcceptable,
+			body:        `{"message":"could not serialize to any of the accepted formats"}`,
+		},
+		{
+			description: "failure",
+			handler:     NewGamesHandler(serviceFailure),
+			url:         "/games/1/achievements",
+			statusCode:  http.StatusBadGateway,
+			body:        `{"message":"could not get game 1"}`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			router := chi.NewRouter()
+			router.Get("/games/{id}/achievements", tc.handler.GetAchievements)
+
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if rec.Result().StatusCode != tc.statusCode {
+				t.Errorf(
+					"was expecting %d status code, but returns %d",
+					tc.statusCode,
+					rec.Result().StatusCode,
+				)
+			}
+
+			b, err := ioutil.ReadAll(rec.Body)
+			if err != nil {
+				t.Errorf("could not read response content: %v", err)
+			}
+
+			if string(b) != tc.body {
+				t.Errorf("was expecting\n%v\nbut returns\n%v\n", tc.body, string(b))
+			}
+		})
+	}
+}
+
+func TestGamesHandlerGetTimeline(t *testing.T) {
+	serviceSuccess := service.NewMockGamesService(
+		nil,
+		func(id string) (*parser.Game, error) {
+			return &parser.Game{
+				ID:      id,
+				Players: []string{"Isgalamido", "Mocinha"},
+				KillSequence: []*parser.Kill{
+					{Killer: "Isgalamido", Dead: "Mocinha", Means: "MOD_RAILGUN", Time: "0:10"},
+				},
+			}, nil
+		},
+	)
+	serviceFailure := service.NewMockGamesService(
+		nil,
+		func(id string) (*parser.Game, error) {
+			return nil, fmt.Errorf("could not get game %s", id)
+		},
+	)
+
+	tt := []struct {
+		description string
+		handler     GamesHandler
+		url         string
+		statusCode  int
+		contentType string
+		body        string
+	}{
+		{
+			description: "as json",
+			handler:     NewGamesHandler(serviceSuccess),
+			url:         "/games/1/timeline",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			body: `{"id":"1","players":["Isgalamido","Mocinha"],"timeline":[` +
+				`{"time":"0:10","killer":"Isgalamido","dead":"Mocinha","means":"MOD_RAILGUN","scores":{"Isgalamido":1,"Mocinha":0}}]}`,
+		},
+		{
+			description: "as csv",
+			handler:     NewGamesHandler(serviceSuccess),
+			url:         "/games/1/timeline?format=csv",
+			statusCode:  http.StatusOK,
+			contentType: "text/csv",
+			body: "game_id,time,killer,dead,means,player,score\n" +
+				"1,0:10,Isgalamido,Mocinha,MOD_RAILGUN,Isgalamido,1\n" +
+				"1,0:10,Isgalamido,Mocinha,MOD_RAILGUN,Mocinha,0\n",
+		},
+		{
+			description: "unsupported format",
+			handler:     NewGamesHandler(serviceSuccess),
+			url:         "/games/1/timeline?format=xml",
+			statusCode:  http.StatusNotAcceptable,
+			contentType: "application/json",
+			body:        `{"message":"could not serialize to any of the accepted formats"}`,
+		},
+		{
+			description: "failure",
+			handler:     NewGamesHandler(serviceFailure),
+			url:         "/games/1/timeline",
+			statusCode:  http.StatusBadGateway,
+			contentType: "application/json",
+			body:        `{"message":"could not get game 1"}`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			router := chi.NewRouter()
+			router.Get("/games/{id}/timeline", tc.handler.GetTimeline)
+
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if rec.Result().StatusCode != tc.statusCode {
+				t.Errorf(
+					"was expecting %d status code, but returns %d",
+					tc.statusCode,
+					rec.Result().StatusCode,
+				)
+			}
+
+			if ct := rec.Result().Header.Get("Content-Type"); ct != tc.contentType {
+				t.Errorf("was expecting %s content type, but returns %s", tc.contentType, ct)
+			}
+
+			b, err := ioutil.ReadAll(rec.Body)
+			if err != nil {
+				t.Errorf("could not read response content: %v", err)
+			}
+
+			if string(b) != tc.body {
+				t.Errorf("was expecting\n%v\nbut returns\n%v\n", tc.body, string(b))
+			}
+		})
+	}
 }
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bgildson/enext-challenge/api/service"
+)
+
+// HealthHandler indicates how to implements a new HealthHandler
+type HealthHandler interface {
+	Healthz(http.ResponseWriter, *http.Request)
+	Readyz(http.ResponseWriter, *http.Request)
+}
+
+type healthHandler struct {
+	service service.GamesService
+}
+
+// health represents the api health status
+type health struct {
+	Status string `json:"status"`
+	Games  *int   `json:"games,omitempty"`
+}
+
+// NewHealthHandler creates a new HealthHandler instance
+func NewHealthHandler(service service.GamesService) HealthHandler {
+	return &healthHandler{service}
+}
+
+// Healthz replies while the api is able to handle requests
+func (h *healthHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	b, _ := json.Marshal(&health{Status: "ok"})
+	handleSuccess(w, http.StatusOK, "application/json", b)
+}
+
+// Readyz replies successfully only when the games could be loaded from the database
+func (h *healthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	games, err := h.service.List()
+	if err != nil {
+		handleFailure(w, http.StatusServiceUnavailable, err)
+		return
+	}
+
+	count := len(games)
+	b, _ := json.Marshal(&health{Status: "ready", Games: &count})
+	handleSuccess(w, http.StatusOK, "application/json", b)
+}
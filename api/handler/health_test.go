@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bgildson/enext-challenge/api/service"
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+func TestHealthHandler(t *testing.T) {
+	serviceSuccess := service.NewMockGamesService(
+		func() ([]*parser.Game, error) {
+			return []*parser.Game{{ID: "1"}, {ID: "2"}}, nil
+		},
+		nil,
+	)
+	serviceFailure := service.NewMockGamesService(
+		func() ([]*parser.Game, error) {
+			return nil, fmt.Errorf("could not load games")
+		},
+		nil,
+	)
+
+	tt := []struct {
+		description string
+		handle      http.HandlerFunc
+		statusCode  int
+		body        string
+	}{
+		{
+			description: "healthy",
+			handle:      NewHealthHandler(serviceFailure).Healthz,
+			statusCode:  http.StatusOK,
+			body:        `{"status":"ok"}`,
+		},
+		{
+			description: "ready",
+			handle:      NewHealthHandler(serviceSuccess).Readyz,
+			statusCode:  http.StatusOK,
+			body:        `{"status":"ready","games":2}`,
+		},
+		{
+			description: "not ready",
+			handle:      NewHealthHandler(serviceFailure).Readyz,
+			statusCode:  http.StatusServiceUnavailable,
+			body:        `{"message":"could not load games"}`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			tc.handle(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rec.Result().StatusCode != tc.statusCode {
+				t.Errorf(
+					"was expecting %d status code, but returns %d",
+					tc.statusCode,
+					rec.Result().StatusCode,
+				)
+			}
+
+			b, err := ioutil.ReadAll(rec.Body)
+			if err != nil {
+				t.Errorf("could not read response content: %v", err)
+			}
+
+			if string(b) != tc.body {
+				t.Errorf("was expecting\n%v\nbut returns\n%v\n", tc.body, string(b))
+			}
+		})
+	}
+}
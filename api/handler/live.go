@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/bgildson/enext-challenge/api/live"
+)
+
+// LiveHandler indicates how to implements a new LiveHandler
+type LiveHandler interface {
+	Stream(http.ResponseWriter, *http.Request)
+}
+
+type liveHandler struct {
+	hub live.Hub
+}
+
+// NewLiveHandler creates a new LiveHandler instance
+func NewLiveHandler(hub live.Hub) LiveHandler {
+	return &liveHandler{hub}
+}
+
+// Stream pushes the hub events to the client using Server-Sent Events
+func (h *liveHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		handleFailure(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported"))
+		return
+	}
+
+	s, err := h.hub.Subscribe()
+	if err != nil {
+		handleFailure(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	defer h.hub.Unsubscribe(s)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-s.Events():
+			// the hub dropped this client
+			if !ok {
+				return
+			}
+
+			b, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, b)
+			flusher.Flush()
+		}
+	}
+}
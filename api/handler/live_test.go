@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bgildson/enext-challenge/api/live"
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+func TestLiveHandler(t *testing.T) {
+	hub := live.NewHub(8)
+	h := NewLiveHandler(hub)
+
+	srv := httptest.NewServer(http.HandlerFunc(h.Stream))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("an unexpected error occurred: %v", err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("an unexpected error occurred: %v", err)
+	}
+	defer res.Body.Close()
+
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("was expecting text/event-stream content type, but returns %s", ct)
+	}
+
+	// wait the handler subscribe before publishing
+	for hub.Clients() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	src := live.NewMockSource(func(ctx context.Context, publish func(*parser.Event)) error {
+		publish(&parser.Event{Type: parser.EventGameStart, GameID: "1", Time: "0:00"})
+		publish(&parser.Event{Type: parser.EventKill, GameID: "1", Time: "0:10", Killer: "<world>", Dead: "Isgalamido", Means: "MOD_FALLING"})
+		return nil
+	})
+	if err := hub.Run(ctx, src); err != nil {
+		t.Errorf("an unexpected error occurred: %v", err)
+	}
+
+	expected := []string{
+		`event: game_start`,
+		`data: {"type":"game_start","game_id":"1","time":"0:00"}`,
+		``,
+		`event: kill`,
+		`data: {"type":"kill","game_id":"1","time":"0:10","killer":"\u003cworld\u003e","dead":"Isgalamido","means":"MOD_FALLING"}`,
+		``,
+	}
+	s := bufio.NewScanner(res.Body)
+	var received []string
+	for range expected {
+		if !s.Scan() {
+			break
+		}
+		received = append(received, s.Text())
+	}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("was expecting\n%v\nbut returns\n%v\n", expected, received)
+	}
+}
+
+func TestLiveHandlerStopped(t *testing.T) {
+	hub := live.NewHub(8)
+	src := live.NewMockSource(func(ctx context.Context, publish func(*parser.Event)) error {
+		return fmt.Errorf("could not read the log")
+	})
+	hub.Run(context.Background(), src)
+
+	rec := httptest.NewRecorder()
+
+	NewLiveHandler(hub).Stream(rec, httptest.NewRequest(http.MethodGet, "/live", nil))
+
+	if rec.Result().StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("was expecting %d status code, but returns %d", http.StatusServiceUnavailable, rec.Result().StatusCode)
+	}
+
+	b, err := ioutil.ReadAll(rec.Body)
+	if err != nil {
+		t.Errorf("could not read response content: %v", err)
+	}
+
+	if body := `{"message":"the live events stopped"}`; string(b) != body {
+		t.Errorf("was expecting\n%v\nbut returns\n%v\n", body, string(b))
+	}
+}
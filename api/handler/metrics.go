@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/bgildson/enext-challenge/api/metrics"
+	"github.com/bgildson/enext-challenge/api/service"
+)
+
+// MetricsHandler indicates how to implements a new MetricsHandler
+type MetricsHandler interface {
+	Metrics(http.ResponseWriter, *http.Request)
+}
+
+type metricsHandler struct {
+	collector metrics.Collector
+	service   service.GamesService
+}
+
+// NewMetricsHandler creates a new MetricsHandler instance
+func NewMetricsHandler(collector metrics.Collector, service service.GamesService) MetricsHandler {
+	return &metricsHandler{collector, service}
+}
+
+// Metrics replies the request metrics and the database size in the prometheus text format
+func (h *metricsHandler) Metrics(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+
+	if err := h.collector.Write(&buf); err != nil {
+		handleFailure(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// the database size is only reported when it could be loaded
+	if games, err := h.service.List(); err == nil {
+		fmt.Fprintf(&buf, "# HELP games_database_size Number of games loaded in the database.\n")
+		fmt.Fprintf(&buf, "# TYPE games_database_size gauge\n")
+		fmt.Fprintf(&buf, "games_database_size %d\n", len(games))
+	}
+
+	handleSuccess(w, http.StatusOK, "text/plain; version=0.0.4", buf.Bytes())
+}
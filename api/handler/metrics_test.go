@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bgildson/enext-challenge/api/metrics"
+	"github.com/bgildson/enext-challenge/api/service"
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	collector := metrics.NewCollector([]float64{1})
+	collector.Observe(http.MethodGet, "/games", http.StatusOK, time.Millisecond)
+
+	serviceSuccess := service.NewMockGamesService(
+		func() ([]*parser.Game, error) {
+			return []*parser.Game{{ID: "1"}, {ID: "2"}, {ID: "3"}}, nil
+		},
+		nil,
+	)
+	serviceFailure := service.NewMockGamesService(
+		func() ([]*parser.Game, error) {
+			return nil, fmt.Errorf("could not load games")
+		},
+		nil,
+	)
+
+	tt := []struct {
+		description string
+		service     service.GamesService
+		contains    []string
+		missing     []string
+	}{
+		{
+			description: "with the database loaded",
+			service:     serviceSuccess,
+			contains: []string{
+				`http_requests_total{method="GET",route="/games",status="200"} 1`,
+				`games_database_size 3`,
+			},
+		},
+		{
+			description: "without the database loaded",
+			service:     serviceFailure,
+			contains: []string{
+				`http_requests_total{method="GET",route="/games",status="200"} 1`,
+			},
+			missing: []string{
+				`games_database_size`,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			NewMetricsHandler(collector, tc.service).Metrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+			if rec.Result().StatusCode != http.StatusOK {
+				t.Errorf(
+					"was expecting %d status code, but returns %d",
+					http.StatusOK,
+					rec.Result().StatusCode,
+				)
+			}
+
+			b, err := ioutil.ReadAll(rec.Body)
+			if err != nil {
+				t.Errorf("could not read response content: %v", err)
+			}
+
+			for _, c := range tc.contains {
+				if !strings.Contains(string(b), c) {
+					t.Errorf("was expecting\n%v\nin\n%v", c, string(b))
+				}
+			}
+			for _, m := range tc.missing {
+				if strings.Contains(string(b), m) {
+					t.Errorf("was not expecting\n%v\nin\n%v", m, string(b))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bgildson/enext-challenge/api/service"
+	"github.com/bgildson/enext-challenge/report"
+	"github.com/go-chi/chi"
+)
+
+// RankingHandler indicates how to implements a new RankingHandler
+type RankingHandler interface {
+	GetTeams(http.ResponseWriter, *http.Request)
+	GetWeapons(http.ResponseWriter, *http.Request)
+	GetPlayerWeapons(http.ResponseWriter, *http.Request)
+	GetRankingSVG(http.ResponseWriter, *http.Request)
+	GetRanking(http.ResponseWriter, *http.Request)
+}
+
+type rankingHandler struct {
+	service service.GamesService
+}
+
+// teamRanking represents the team ranking of the team games
+type teamRanking struct {
+	Games int            `json:"games"`
+	Teams []*report.Team `json:"teams"`
+}
+
+// weaponRanking represents the weapon stats of all the games
+type weaponRanking struct {
+	TotalKills int                     `json:"total_kills"`
+	Weapons    []*report.Weapon        `json:"weapons"`
+	Players    []*report.PlayerWeapons `json:"players"`
+}
+
+// generalRanking represents the players ranked by the ranking config, which is described with them
+type generalRanking struct {
+	Mode        string          `json:"mode"`
+	MinGames    int             `json:"min_games"`
+	CTFBonus    bool            `json:"ctf_bonus"`
+	Description string          `json:"description"`
+	TotalKills  int             `json:"total_kills"`
+	Players     []*report.Score `json:"players"`
+}
+
+// NewRankingHandler creates a new RankingHandler instance
+func NewRankingHandler(service service.GamesService) RankingHandler {
+	return &rankingHandler{service}
+}
+
+// GetTeams replies the teams ranked by their results in the team games
+func (h *rankingHandler) GetTeams(w http.ResponseWriter, r *http.Request) {
+	f, err := negotiateJSON(r)
+	if err != nil {
+		handleFailure(w, http.StatusNotAcceptable, err)
+		return
+	}
+
+	games, err := h.service.List()
+	if err != nil {
+		handleFailure(w, http.StatusBadGateway, err)
+		return
+	}
+
+	ranking := report.NewTeamRanking()
+	for _, g := range games {
+		ranking.AddGame(g)
+	}
+
+	teams := ranking.Ordered()
+	if teams == nil {
+		teams = []*report.Team{}
+	}
+
+	b, err := json.Marshal(&teamRanking{Games: ranking.Games, Teams: teams})
+	if err != nil {
+		handleFailure(w, http.StatusBadGateway, err)
+		return
+	}
+
+	handleSuccess(w, http.StatusOK, f.ContentType, b)
+}
+
+// GetWeapons replies the weapons ranked by their kills and the weapon stats of every player
+func (h *rankingHandler) GetWeapons(w http.ResponseWriter, r *http.Request) {
+	f, err := negotiateJSON(r)
+	if err != nil {
+		handleFailure(w, http.StatusNotAcceptable, err)
+		return
+	}
+
+	ranking, err := h.weapons()
+	if err != nil {
+		handleFailure(w, http.StatusBadGateway, err)
+		return
+	}
+
+	weapons := ranking.Ordered()
+	if weapons == nil {
+		weapons = []*report.Weapon{}
+	}
+	players := ranking.OrderedPlayers()
+	if players == nil {
+		players = []*report.PlayerWeapons{}
+	}
+
+	b, err := json.Marshal(&weaponRanking{TotalKills: ranking.TotalKills, Weapons: weapons, Players: players})
+	if err != nil {
+		handleFailure(w, http.StatusBadGateway, err)
+		return
+	}
+
+	handleSuccess(w, http.StatusOK, f.ContentType, b)
+}
+
+// GetPlayerWeapons replies the weapon stats of one player
+func (h *rankingHandler) GetPlayerWeapons(w http.ResponseWriter, r *http.Request) {
+	// the player names can have spaces and other escaped characters
+	name := chi.URLParam(r, "name")
+	if n, err := url.PathUnescape(name); err == nil {
+		name = n
+	}
+
+	f, err := negotiateJSON(r)
+	if err != nil {
+		handleFailure(w, http.StatusNotAcceptable, err)
+		return
+	}
+
+	ranking, err := h.weapons()
+	if err != nil {
+		handleFailure(w, http.StatusBadGateway, err)
+		return
+	}
+
+	p, ok := ranking.Players[name]
+	if !ok {
+		handleFailure(w, http.StatusNotFound, fmt.Errorf("player %s not found", name))
+		return
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		handleFailure(w, http.StatusBadGateway, err)
+		return
+	}
+
+	handleSuccess(w, http.StatusOK, f.ContentType, b)
+}
+
+func (h *rankingHandler) weapons() (*report.WeaponRanking, error) {
+	games, err := h.service.List()
+	if err != nil {
+		return nil, err
+	}
+
+	ranking := report.NewWeaponRanking()
+	for _, g := range games {
+		ranking.AddGame(g)
+	}
+	return ranking, nil
+}
+
+// GetRankingSVG replies the general ranking drawn as a bar chart
+func (h *rankingHandler) GetRankingSVG(w http.ResponseWriter, r *http.Request) {
+	games, err := h.service.List()
+	if err != nil {
+		handleFailure(w, http.StatusBadGateway, err)
+		return
+	}
+
+	ranking := report.NewRanking()
+	for _, g := range games {
+		ranking.AddGame(g)
+	}
+
+	handleSuccess(w, http.StatusOK, "image/svg+xml", []byte(report.RankingSVG(ranking)))
+}
+
+// GetRanking replies the players ranked by the mode query param, total, average or per_minute,
+// only with the players who played the min_games query param, like ?mode=average&min_games=3,
+// the flag actions score with ?ctf_bonus=true
+func (h *rankingHandler) GetRanking(w http.ResponseWriter, r *http.Request) {
+	f, err := negotiateJSON(r)
+	if err != nil {
+		handleFailure(w, http.StatusNotAcceptable, err)
+		return
+	}
+
+	c, err := rankingConfig(r)
+	if err != nil {
+		handleFailure(w, http.StatusBadRequest, err)
+		return
+	}
+
+	games, err := h.service.List()
+	if err != nil {
+		handleFailure(w, http.StatusBadGateway, err)
+		return
+	}
+
+	ranking := report.NewNormalizedRanking(c)
+	for _, g := range games {
+		ranking.AddGame(g)
+	}
+
+	players := ranking.Ordered()
+	if players == nil {
+		players = []*report.Score{}
+	}
+
+	b, err := json.Marshal(&generalRanking{
+		Mode:        c.Mode,
+		MinGames:    c.MinGames,
+		CTFBonus:    c.CTFBonus,
+		Description: c.Description(),
+		TotalKills:  ranking.TotalKills,
+		Players:     players,
+	})
+	if err != nil {
+		handleFailure(w, http.StatusBadGateway, err)
+		return
+	}
+
+	handleSuccess(w, http.StatusOK, f.ContentType, b)
+}
+
+// rankingConfig reads the ranking config from the query params, using the defaults for the missing ones
+func rankingConfig(r *http.Request) (report.RankingConfig, error) {
+	c := report.DefaultRankingConfig
+	q := r.URL.Query()
+
+	if v := q.Get("mode"); v != "" {
+		c.Mode = v
+	}
+	if v := q.Get("min_games"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return c, fmt.Errorf("invalid min_games %q", v)
+		}
+		c.MinGames = n
+	}
+	if v := q.Get("ctf_bonus"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return c, fmt.Errorf("invalid ctf_bonus %q", v)
+		}
+		c.CTFBonus = b
+	}
+
+	return c, c.Validate()
+}
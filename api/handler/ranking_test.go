@@ -0,0 +1,413 @@
+package handler
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bgildson/enext-challenge/api/service"
+	"github.com/bgildson/enext-challenge/parser"
+	"github.com/go-chi/chi"
+)
+
+func TestRankingHandlerGetTeams(t *testing.T) {
+	serviceSuccess := service.NewMockGamesService(
+		func() ([]*parser.Game, error) {
+			return []*parser.Game{
+				{
+					ID:           "1",
+					GameType:     parser.GameTypeTDM,
+					TotalKills:   3,
+					Players:      []string{"Isgalamido", "Mocinha"},
+					Kills:        map[string]int{"Isgalamido": 2, "Mocinha": -1},
+					TeamChanges:  []*parser.TeamChange{{Player: "Mocinha", Team: parser.TeamBlue, Time: "0:01"}},
+					TeamKills:    map[string]int{parser.TeamRed: 2},
+					FriendlyFire: map[string]int{"Mocinha": 1},
+				},
+				{ID: "2", TotalKills: 1},
+			}, nil
+		},
+		nil,
+	)
+	serviceEmpty := service.NewMockGamesService(
+		func() ([]*parser.Game, error) {
+			return []*parser.Game{{ID: "1", TotalKills: 1}}, nil
+		},
+		nil,
+	)
+	serviceFailure := service.NewMockGamesService(
+		func() ([]*parser.Game, error) {
+			return nil, fmt.Errorf("could not load games")
+		},
+		nil,
+	)
+
+	tt := []struct {
+		description string
+		handle      http.HandlerFunc
+		url         string
+		statusCode  int
+		body        string
+	}{
+		{
+			description: "success",
+			handle:      NewRankingHandler(serviceSuccess).GetTeams,
+			url:         "/teams",
+			statusCode:  http.StatusOK,
+			body: `{"games":1,"teams":[` +
+				`{"name":"red","wins":1,"score":2,"kills":2,"friendly_fire":0},` +
+				`{"name":"blue","wins":0,"score":0,"kills":0,"friendly_fire":1}]}`,
+		},
+		{
+			description: "without team games",
+			handle:      NewRankingHandler(serviceEmpty).GetTeams,
+			url:         "/teams",
+			statusCode:  http.StatusOK,
+			body:        `{"games":0,"teams":[]}`,
+		},
+		{
+			description: "unsupported format",
+			handle:      NewRankingHandler(serviceSuccess).GetTeams,
+			url:         "/teams?format=csv",
+			statusCode:  http.StatusNotAcceptable,
+			body:        `{"message":"could not serialize to any of the accepted formats"}`,
+		},
+		{
+			description: "failure",
+			handle:      NewRankingHandler(serviceFailure).GetTeams,
+			url:         "/teams",
+			statusCode:  http.StatusBadGateway,
+			body:        `{"message":"could not load games"}`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			tc.handle(rec, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if rec.Result().StatusCode != tc.statusCode {
+				t.Errorf(
+					"was expecting %d status code, but returns %d",
+					tc.statusCode,
+					rec.Result().StatusCode,
+				)
+			}
+
+			b, err := ioutil.ReadAll(rec.Body)
+			if err != nil {
+				t.Errorf("could not read response content: %v", err)
+			}
+
+			if string(b) != tc.body {
+				t.Errorf("was expecting\n%v\nbut returns\n%v\n", tc.body, string(b))
+			}
+		})
+	}
+}
+
+func TestRankingHandlerWeapons(t *testing.T) {
+	serviceSuccess := service.NewMockGamesService(
+		func() ([]*parser.Game, error) {
+			return []*parser.Game{
+				{
+					ID:         "1",
+					TotalKills: 2,
+					Players:    []string{"Dono da Bola", "Mocinha"},
+					Kills:      map[string]int{"Dono da Bola": 1, "Mocinha": -1},
+					Weapons: map[string]*parser.WeaponStats{
+						"Dono da Bola": {Kills: map[string]int{"MOD_RAILGUN": 1}},
+						"Mocinha":      {Deaths: map[string]int{"MOD_RAILGUN": 1, "MOD_FALLING": 1}},
+					},
+				},
+			}, nil
+		},
+		nil,
+	)
+	serviceFailure := service.NewMockGamesService(
+		func() ([]*parser.Game, error) {
+			return nil, fmt.Errorf("could not load games")
+		},
+		nil,
+	)
+
+	tt := []struct {
+		description string
+		handler     RankingHandler
+		url         string
+		statusCode  int
+		body        string
+	}{
+		{
+			description: "all the weapons",
+			handler:     NewRankingHandler(serviceSuccess),
+			url:         "/weapons",
+			statusCode:  http.StatusOK,
+			body: `{"total_kills":1,"weapons":[{"name":"MOD_RAILGUN","kills":1,"share":1}],"players":[` +
+				`{"name":"Dono da Bola","favourite":"MOD_RAILGUN","kills":{"MOD_RAILGUN":1},"deaths":{}},` +
+				`{"name":"Mocinha","kills":{},"deaths":{"MOD_FALLING":1,"MOD_RAILGUN":1}}]}`,
+		},
+		{
+			description: "the weapons of a player",
+			handler:     NewRankingHandler(serviceSuccess),
+			url:         "/players/Dono%20da%20Bola/weapons",
+			statusCode:  http.StatusOK,
+			body:        `{"name":"Dono da Bola","favourite":"MOD_RAILGUN","kills":{"MOD_RAILGUN":1},"deaths":{}}`,
+		},
+		{
+			description: "the weapons of an unknown player",
+			handler:     NewRankingHandler(serviceSuccess),
+			url:         "/players/Zeh/weapons",
+			statusCode:  http.StatusNotFound,
+			body:        `{"message":"player Zeh not found"}`,
+		},
+		{
+			description: "the weapons in an unsupported format",
+			handler:     NewRankingHandler(serviceSuccess),
+			url:         "/weapons?format=xml",
+			statusCode:  http.StatusNotAcceptable,
+			body:        `{"message":"could not serialize to any of the accepted formats"}`,
+		},
+		{
+			description: "the weapons of a player in an unsupported format",
+			handler:     NewRankingHandler(serviceSuccess),
+			url:         "/players/Mocinha/weapons?format=msgpack",
+			statusCode:  http.StatusNotAcceptable,
+			body:        `{"message":"could not serialize to any of the accepted formats"}`,
+		},
+		{
+			description: "failure",
+			handler:     NewRankingHandler(serviceFailure),
+			url:         "/weapons",
+			statusCode:  http.StatusBadGateway,
+			body:        `{"message":"could not load games"}`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			router := chi.NewRouter()
+			router.Get("/weapons", tc.handler.GetWeapons)
+			router.Get("/players/{name}/weapons", tc.handler.GetPlayerWeapons)
+
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if rec.Result().StatusCode != tc.statusCode {
+				t.Errorf(
+					"was expecting %d status code, but returns %d",
+					tc.statusCode,
+					rec.Result().StatusCode,
+				)
+			}
+
+			b, err := ioutil.ReadAll(rec.Body)
+			if err != nil {
+				t.Errorf("could not read response content: %v", err)
+			}
+
+			if string(b) != tc.body {
+				t.Errorf("was expecting\n%v\nbut returns\n%v\n", tc.body, string(b))
+			}
+		})
+	}
+}
+
+func TestRankingHandlerGetRankingSVG(t *testing.T) {
+	serviceSuccess := service.NewMockGamesService(
+		func() ([]*parser.Game, error) {
+			return []*parser.Game{
+				{ID: "1", TotalKills: 2, Players: []string{"Isgalamido"}, Kills: map[string]int{"Isgalamido": 2}},
+			}, nil
+		},
+		nil,
+	)
+	serviceFailure := service.NewMockGamesService(
+		func() ([]*parser.Game, error) {
+			return nil, fmt.Errorf("could not load games")
+		},
+		nil,
+	)
+
+	tt := []struct {
+		description string
+		handle      http.HandlerFunc
+		statusCode  int
+		contentType string
+		contains    string
+	}{
+		{
+			description: "success",
+			handle:      NewRankingHandler(serviceSuccess).GetRankingSVG,
+			statusCode:  http.StatusOK,
+			contentType: "image/svg+xml",
+			contains:    `<text x="192.0" y="54.0" text-anchor="end">Isgalamido</text>`,
+		},
+		{
+			description: "failure",
+			handle:      NewRankingHandler(serviceFailure).GetRankingSVG,
+			statusCode:  http.StatusBadGateway,
+			contentType: "application/json",
+			contains:    `{"message":"could not load games"}`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			tc.handle(rec, httptest.NewRequest(http.MethodGet, "/ranking.svg", nil))
+
+			if rec.Result().StatusCode != tc.statusCode {
+				t.Errorf(
+					"was expecting %d status code, but returns %d",
+					tc.statusCode,
+					rec.Result().StatusCode,
+				)
+			}
+
+			if ct := rec.Result().Header.Get("Content-Type"); ct != tc.contentType {
+				t.Errorf("was expecting %s content type, but returns %s", tc.contentType, ct)
+			}
+
+			b, err := ioutil.ReadAll(rec.Body)
+			if err != nil {
+				t.Errorf("could not read response content: %v", err)
+			}
+
+			if !strings.Contains(string(b), tc.contains) {
+				t.Errorf("was expecting\n%v\nin\n%v\n", tc.contains, string(b))
+			}
+		})
+	}
+}
+
+func TestRankingHandlerGetRanking(t *testing.T) {
+	serviceSuccess := service.NewMockGamesService(
+		func() ([]*parser.Game, error) {
+			return []*parser.Game{
+				{
+					ID:         "1",
+					TotalKills: 2,
+					Players:    []string{"Isgalamido"},
+					Kills:      map[string]int{"Isgalamido": 2},
+					PlayTime:   map[string]int{"Isgalamido": 120},
+				},
+				{
+					ID:         "2",
+					TotalKills: 9,
+					Players:    []string{"Isgalamido", "Mocinha"},
+					Kills:      map[string]int{"Isgalamido": 4, "Mocinha": 5},
+					PlayTime:   map[string]int{"Isgalamido": 60, "Mocinha": 60},
+				},
+			}, nil
+		},
+		nil,
+	)
+	serviceFailure := service.NewMockGamesService(
+		func() ([]*parser.Game, error) {
+			return nil, fmt.Errorf("could not load games")
+		},
+		nil,
+	)
+
+	tt := []struct {
+		description string
+		service     service.GamesService
+		query       string
+		statusCode  int
+		out         string
+	}{
+		{
+			description: "total",
+			service:     serviceSuccess,
+			statusCode:  http.StatusOK,
+			out:         `{"mode":"total","min_games":0,"ctf_bonus":false,"description":"total points","total_kills":11,"players":[{"name":"Isgalamido","points":6,"games":2,"minutes":3,"score":6},{"name":"Mocinha","points":5,"games":1,"minutes":1,"score":5}]}`,
+		},
+		{
+			description: "average",
+			service:     serviceSuccess,
+			query:       "?mode=average",
+			statusCode:  http.StatusOK,
+			out:         `{"mode":"average","min_games":0,"ctf_bonus":false,"description":"average points per game","total_kills":11,"players":[{"name":"Mocinha","points":5,"games":1,"minutes":1,"score":5},{"name":"Isgalamido","points":6,"games":2,"minutes":3,"score":3}]}`,
+		},
+		{
+			description: "per minute with minimum games",
+			service:     serviceSuccess,
+			query:       "?mode=per_minute&min_games=2",
+			statusCode:  http.StatusOK,
+			out:         `{"mode":"per_minute","min_games":2,"ctf_bonus":false,"description":"points per minute played, at least 2 games","total_kills":11,"players":[{"name":"Isgalamido","points":6,"games":2,"minutes":3,"score":2}]}`,
+		},
+		{
+			description: "nobody eligible",
+			service:     serviceSuccess,
+			query:       "?min_games=3",
+			statusCode:  http.StatusOK,
+			out:         `{"mode":"total","min_games":3,"ctf_bonus":false,"description":"total points, at least 3 games","total_kills":11,"players":[]}`,
+		},
+		{
+			description: "invalid ctf bonus",
+			service:     serviceSuccess,
+			query:       "?ctf_bonus=maybe",
+			statusCode:  http.StatusBadRequest,
+			out:         `{"message":"invalid ctf_bonus \"maybe\""}`,
+		},
+		{
+			description: "invalid mode",
+			service:     serviceSuccess,
+			query:       "?mode=best",
+			statusCode:  http.StatusBadRequest,
+			out:         `{"message":"unknown ranking mode \"best\", should be total, average or per_minute"}`,
+		},
+		{
+			description: "invalid min games",
+			service:     serviceSuccess,
+			query:       "?min_games=-1",
+			statusCode:  http.StatusBadRequest,
+			out:         `{"message":"invalid minimum games -1"}`,
+		},
+		{
+			description: "unsupported format",
+			service:     serviceSuccess,
+			query:       "?format=xml",
+			statusCode:  http.StatusNotAcceptable,
+			out:         `{"message":"could not serialize to any of the accepted formats"}`,
+		},
+		{
+			description: "failure",
+			service:     serviceFailure,
+			statusCode:  http.StatusBadGateway,
+			out:         `{"message":"could not load games"}`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			NewRankingHandler(tc.service).GetRanking(rec, httptest.NewRequest(http.MethodGet, "/ranking"+tc.query, nil))
+
+			if rec.Result().StatusCode != tc.statusCode {
+				t.Errorf(
+					"was expecting %d status code, but returns %d",
+					tc.statusCode,
+					rec.Result().StatusCode,
+				)
+			}
+
+			b, err := ioutil.ReadAll(rec.Body)
+			if err != nil {
+				t.Errorf("could not read response content: %v", err)
+			}
+
+			if strings.TrimSpace(string(b)) != tc.out {
+				t.Errorf("was expecting\n%v\nbut returns\n%v\n", tc.out, string(b))
+			}
+		})
+	}
+}
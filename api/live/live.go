@@ -0,0 +1,252 @@
+package live
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// Reusable errors
+var (
+	ErrStopped = errors.New("the live events stopped")
+)
+
+// Source indicates how to implements a new Source of live events
+type Source interface {
+	// Events publishes the events until the context is done or the source fails
+	Events(ctx context.Context, publish func(*parser.Event)) error
+}
+
+type logSource struct {
+	path     string
+	interval time.Duration
+}
+
+// NewLogSource creates a new Source that follows a log file while the server writes it,
+// reopening it when it is rotated or truncated
+func NewLogSource(path string, interval time.Duration) Source {
+	return &logSource{
+		path:     path,
+		interval: interval,
+	}
+}
+
+func (s *logSource) Events(ctx context.Context, publish func(*parser.Event)) error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+
+	// the content written before is parsed only to know the current game and players,
+	// the events published are the ones written from now on
+	p := parser.NewEventParser()
+	offset, err := skipWritten(f, p)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	for {
+		reopen, err := s.follow(ctx, f, offset, p, publish)
+		f.Close()
+		if !reopen {
+			return err
+		}
+
+		// the new log content is read from the beginning, as the continuation of the old one
+		if f, err = s.open(ctx); err != nil {
+			return err
+		}
+		offset = 0
+	}
+}
+
+// skipWritten parses the complete lines already written, returning the offset after them
+func skipWritten(f *os.File, p *parser.EventParser) (int64, error) {
+	br := bufio.NewReader(f)
+	var offset int64
+	for {
+		line, err := br.ReadString('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		offset += int64(len(line))
+		p.Parse(strings.TrimRight(line, "\r\n"))
+	}
+	_, err := f.Seek(offset, io.SeekStart)
+	return offset, err
+}
+
+// follow publishes the events of the lines appended to the file, like parser.Follow, until the
+// context is done, or until the log was rotated or truncated, when the file should be opened again
+func (s *logSource) follow(ctx context.Context, f *os.File, offset int64, p *parser.EventParser, publish func(*parser.Event)) (bool, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	br := bufio.NewReader(f)
+
+	// keeps the content of a line that was not completely written yet
+	partial := ""
+	for {
+		line, err := br.ReadString('\n')
+		partial += line
+		offset += int64(len(line))
+
+		if err == nil {
+			for _, e := range p.Parse(strings.TrimRight(partial, "\r\n")) {
+				publish(e)
+			}
+			partial = ""
+			continue
+		}
+		if err != io.EOF {
+			return false, err
+		}
+
+		// everything written was read, the log continues in another file when the path was
+		// rotated to a new file or truncated, a missing path is a rotation still in progress
+		if current, err := os.Stat(s.path); err == nil && (!os.SameFile(info, current) || current.Size() < offset) {
+			return true, nil
+		}
+
+		// wait for new content to be appended
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(s.interval):
+		}
+	}
+}
+
+// open waits the log to be created again after a rotation
+func (s *logSource) open(ctx context.Context) (*os.File, error) {
+	for {
+		f, err := os.Open(s.path)
+		if err == nil {
+			return f, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(s.interval):
+		}
+	}
+}
+
+// Subscription receives the events published in a Hub
+type Subscription struct {
+	events chan *parser.Event
+}
+
+// Events returns the channel where the events are delivered,
+// it is closed when the subscription ends or when the subscriber was too slow
+func (s *Subscription) Events() <-chan *parser.Event {
+	return s.events
+}
+
+// Hub indicates how to implements a new Hub
+type Hub interface {
+	Subscribe() (*Subscription, error)
+	Unsubscribe(s *Subscription)
+	Publish(e *parser.Event)
+	Run(ctx context.Context, src Source) error
+	Clients() int
+}
+
+type hub struct {
+	mu      sync.Mutex
+	buffer  int
+	clients map[*Subscription]struct{}
+	// stopped indicates the source ended, so no events will be published anymore
+	stopped bool
+}
+
+// NewHub creates a new Hub that fans out events to every subscription,
+// buffer indicates how many events one subscription can hold before being dropped
+func NewHub(buffer int) Hub {
+	return &hub{
+		buffer:  buffer,
+		clients: map[*Subscription]struct{}{},
+	}
+}
+
+func (h *hub) Subscribe() (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.stopped {
+		return nil, ErrStopped
+	}
+
+	s := &Subscription{
+		events: make(chan *parser.Event, h.buffer),
+	}
+	h.clients[s] = struct{}{}
+
+	return s, nil
+}
+
+func (h *hub) Unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.clients[s]; ok {
+		delete(h.clients, s)
+		close(s.events)
+	}
+}
+
+func (h *hub) Publish(e *parser.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for s := range h.clients {
+		select {
+		case s.events <- e:
+		default:
+			// the subscriber could not keep up, drop it instead of blocking everyone
+			delete(h.clients, s)
+			close(s.events)
+		}
+	}
+}
+
+// Run publishes the source events, returning the error that stopped the source
+func (h *hub) Run(ctx context.Context, src Source) error {
+	err := src.Events(ctx, h.Publish)
+
+	// without a source there is nothing more to wait, so the subscriptions are released
+	// and the next ones are refused
+	h.mu.Lock()
+	h.stopped = true
+	for s := range h.clients {
+		delete(h.clients, s)
+		close(s.events)
+	}
+	h.mu.Unlock()
+
+	return err
+}
+
+func (h *hub) Clients() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.clients)
+}
@@ -0,0 +1,20 @@
+package live
+
+import (
+	"context"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+type mockSource struct {
+	events func(ctx context.Context, publish func(*parser.Event)) error
+}
+
+// NewMockSource generates a new Source instance for mock events
+func NewMockSource(events func(ctx context.Context, publish func(*parser.Event)) error) Source {
+	return &mockSource{events}
+}
+
+func (s *mockSource) Events(ctx context.Context, publish func(*parser.Event)) error {
+	return s.events(ctx, publish)
+}
@@ -0,0 +1,193 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+func TestHub(t *testing.T) {
+	events := []*parser.Event{
+		{Type: parser.EventGameStart, GameID: "1", Time: "0:00"},
+		{Type: parser.EventJoin, GameID: "1", Time: "0:01", Player: "Isgalamido"},
+		{Type: parser.EventKill, GameID: "1", Time: "0:10", Killer: "<world>", Dead: "Isgalamido", Means: "MOD_FALLING"},
+	}
+
+	t.Run("fan out events to every subscription", func(t *testing.T) {
+		h := NewHub(len(events))
+		a, _ := h.Subscribe()
+		b, _ := h.Subscribe()
+
+		src := NewMockSource(func(ctx context.Context, publish func(*parser.Event)) error {
+			for _, e := range events {
+				publish(e)
+			}
+			return nil
+		})
+		if err := h.Run(context.Background(), src); err != nil {
+			t.Errorf("an unexpected error occurred: %v", err)
+		}
+
+		for _, s := range []*Subscription{a, b} {
+			var received []*parser.Event
+			for range events {
+				received = append(received, <-s.Events())
+			}
+			if !reflect.DeepEqual(received, events) {
+				t.Errorf("was expecting %v, but returns %v", events, received)
+			}
+			if _, ok := <-s.Events(); ok {
+				t.Errorf("was expecting the subscription to be closed after the source ends")
+			}
+		}
+	})
+
+	t.Run("refuse subscriptions after the source fails", func(t *testing.T) {
+		h := NewHub(1)
+		failure := errors.New("could not read the log")
+
+		src := NewMockSource(func(ctx context.Context, publish func(*parser.Event)) error {
+			return failure
+		})
+		if err := h.Run(context.Background(), src); err != failure {
+			t.Errorf("was expecting %v, but returns %v", failure, err)
+		}
+
+		if s, err := h.Subscribe(); s != nil || err != ErrStopped {
+			t.Errorf("was expecting %v, but returns %v and %v", ErrStopped, s, err)
+		}
+	})
+
+	t.Run("drop slow subscriptions", func(t *testing.T) {
+		h := NewHub(1)
+		slow, _ := h.Subscribe()
+		fast, _ := h.Subscribe()
+
+		h.Publish(events[0])
+		<-fast.Events()
+		h.Publish(events[1])
+
+		if c := h.Clients(); c != 1 {
+			t.Errorf("was expecting 1 client, but returns %d", c)
+		}
+		if e := <-slow.Events(); e != events[0] {
+			t.Errorf("was expecting %v, but returns %v", events[0], e)
+		}
+		if _, ok := <-slow.Events(); ok {
+			t.Errorf("was expecting the slow subscription to be closed")
+		}
+		if e := <-fast.Events(); e != events[1] {
+			t.Errorf("was expecting %v, but returns %v", events[1], e)
+		}
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		h := NewHub(1)
+		s, _ := h.Subscribe()
+		h.Unsubscribe(s)
+		h.Unsubscribe(s)
+
+		if c := h.Clients(); c != 0 {
+			t.Errorf("was expecting 0 clients, but returns %d", c)
+		}
+		if _, ok := <-s.Events(); ok {
+			t.Errorf("was expecting the subscription to be closed")
+		}
+	})
+}
+
+func TestLogSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logs")
+	if err != nil {
+		t.Fatalf("could not create the logs directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "games.log")
+	write := func(content string, flag int) {
+		f, err := os.OpenFile(path, flag|os.O_WRONLY|os.O_CREATE, 0644)
+		if err != nil {
+			t.Fatalf("could not write the log: %v", err)
+		}
+		f.WriteString(content)
+		f.Close()
+	}
+
+	// the game written before the source started is not published again
+	write("  0:00 InitGame: \\mapname\\q3dm17\n  0:05 ClientUserinfoChanged: 2 n\\Isgalamido\\t\\0\n", os.O_TRUNC)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan *parser.Event, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- NewLogSource(path, time.Millisecond).Events(ctx, func(e *parser.Event) {
+			events <- e
+		})
+	}()
+
+	receive := func(expected []*parser.Event) {
+		t.Helper()
+		var received []*parser.Event
+		for range expected {
+			select {
+			case e := <-events:
+				received = append(received, e)
+			case <-time.After(time.Second):
+			}
+		}
+		if !reflect.DeepEqual(received, expected) {
+			t.Errorf("was expecting %v, but returns %v", expected, received)
+		}
+	}
+
+	t.Run("publish the appended lines", func(t *testing.T) {
+		time.Sleep(10 * time.Millisecond)
+		write("  0:10 Kill: 1022 2 22: <world> killed Isgalamido by MOD_FALLING\n", os.O_APPEND)
+
+		receive([]*parser.Event{
+			{Type: parser.EventKill, GameID: "1", Time: "0:10", Killer: "<world>", Dead: "Isgalamido", Means: "MOD_FALLING"},
+		})
+	})
+
+	t.Run("reopen the rotated log", func(t *testing.T) {
+		if err := os.Rename(path, path+".1"); err != nil {
+			t.Fatalf("could not rotate the log: %v", err)
+		}
+		write("  0:20 ShutdownGame:\n  0:00 InitGame: \\mapname\\q3dm17\n", os.O_TRUNC)
+
+		receive([]*parser.Event{
+			{Type: parser.EventGameEnd, GameID: "1", Time: "0:20"},
+			{Type: parser.EventGameStart, GameID: "2", Time: "0:00"},
+		})
+	})
+
+	t.Run("reopen the truncated log", func(t *testing.T) {
+		time.Sleep(10 * time.Millisecond)
+		write("  0:00 Exit:\n", os.O_TRUNC)
+		write("  0:01 ShutdownGame:\n", os.O_APPEND)
+
+		receive([]*parser.Event{
+			{Type: parser.EventGameEnd, GameID: "2", Time: "0:01"},
+		})
+	})
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("was expecting %v, but returns %v", context.Canceled, err)
+	}
+
+	t.Run("nonexistent log", func(t *testing.T) {
+		if err := NewLogSource(path+".nonexistent", time.Millisecond).Events(context.Background(), nil); err == nil {
+			t.Errorf("was expecting an error, but returns nil")
+		}
+	})
+}
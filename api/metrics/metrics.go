@@ -0,0 +1,166 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the latency histogram
+var DefaultBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// Collector indicates how to implements a new Collector of request metrics
+type Collector interface {
+	Observe(method, route string, status int, d time.Duration)
+	Write(w io.Writer) error
+}
+
+type requestKey struct {
+	method string
+	route  string
+	status int
+}
+
+type latencyKey struct {
+	method string
+	route  string
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+type collector struct {
+	mu        sync.Mutex
+	buckets   []float64
+	requests  map[requestKey]uint64
+	latencies map[latencyKey]*histogram
+}
+
+// NewCollector creates a new Collector that keeps the metrics in memory
+func NewCollector(buckets []float64) Collector {
+	return &collector{
+		buckets:   buckets,
+		requests:  map[requestKey]uint64{},
+		latencies: map[latencyKey]*histogram{},
+	}
+}
+
+func (c *collector) Observe(method, route string, status int, d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests[requestKey{method, route, status}]++
+
+	k := latencyKey{method, route}
+	h, ok := c.latencies[k]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(c.buckets))}
+		c.latencies[k] = h
+	}
+
+	s := d.Seconds()
+	for i, b := range c.buckets {
+		if s <= b {
+			h.counts[i]++
+		}
+	}
+	h.sum += s
+	h.count++
+}
+
+// Write writes the metrics in the prometheus text format
+func (c *collector) Write(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// keys are sorted to generate always the same output
+	requestKeys := make([]requestKey, 0, len(c.requests))
+	for k := range c.requests {
+		requestKeys = append(requestKeys, k)
+	}
+	sort.Slice(requestKeys, func(i, j int) bool {
+		a, b := requestKeys[i], requestKeys[j]
+		if a.route != b.route {
+			return a.route < b.route
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		return a.status < b.status
+	})
+
+	latencyKeys := make([]latencyKey, 0, len(c.latencies))
+	for k := range c.latencies {
+		latencyKeys = append(latencyKeys, k)
+	}
+	sort.Slice(latencyKeys, func(i, j int) bool {
+		a, b := latencyKeys[i], latencyKeys[j]
+		return a.route < b.route || (a.route == b.route && a.method < b.method)
+	})
+
+	var err error
+	printf := func(format string, a ...interface{}) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, a...)
+		}
+	}
+
+	printf("# HELP http_requests_total Total of handled http requests.\n")
+	printf("# TYPE http_requests_total counter\n")
+	for _, k := range requestKeys {
+		printf("http_requests_total{method=%q,route=%q,status=\"%d\"} %d\n", k.method, k.route, k.status, c.requests[k])
+	}
+
+	printf("# HELP http_request_duration_seconds Latency of the handled http requests.\n")
+	printf("# TYPE http_request_duration_seconds histogram\n")
+	for _, k := range latencyKeys {
+		h := c.latencies[k]
+		for i, b := range c.buckets {
+			printf("http_request_duration_seconds_bucket{method=%q,route=%q,le=%q} %d\n", k.method, k.route, formatFloat(b), h.counts[i])
+		}
+		printf("http_request_duration_seconds_bucket{method=%q,route=%q,le=\"+Inf\"} %d\n", k.method, k.route, h.count)
+		printf("http_request_duration_seconds_sum{method=%q,route=%q} %s\n", k.method, k.route, formatFloat(h.sum))
+		printf("http_request_duration_seconds_count{method=%q,route=%q} %d\n", k.method, k.route, h.count)
+	}
+
+	return err
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// Middleware observes every request handled by a chi router, grouping them by route pattern
+func Middleware(c Collector) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r)
+
+			// the route pattern is only known after the routing, unmatched requests are grouped together
+			route := "unmatched"
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			c.Observe(r.Method, route, status, time.Since(start))
+		})
+	}
+}
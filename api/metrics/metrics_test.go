@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+func TestCollector(t *testing.T) {
+	c := NewCollector([]float64{0.1, 1})
+	c.Observe(http.MethodGet, "/games", http.StatusOK, 50*time.Millisecond)
+	c.Observe(http.MethodGet, "/games", http.StatusOK, 500*time.Millisecond)
+	c.Observe(http.MethodGet, "/games/{id}", http.StatusBadGateway, 2*time.Second)
+
+	var buf bytes.Buffer
+	if err := c.Write(&buf); err != nil {
+		t.Errorf("an unexpected error occurred: %v", err)
+	}
+
+	out := `# HELP http_requests_total Total of handled http requests.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",route="/games",status="200"} 2
+http_requests_total{method="GET",route="/games/{id}",status="502"} 1
+# HELP http_request_duration_seconds Latency of the handled http requests.
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{method="GET",route="/games",le="0.1"} 1
+http_request_duration_seconds_bucket{method="GET",route="/games",le="1"} 2
+http_request_duration_seconds_bucket{method="GET",route="/games",le="+Inf"} 2
+http_request_duration_seconds_sum{method="GET",route="/games"} 0.55
+http_request_duration_seconds_count{method="GET",route="/games"} 2
+http_request_duration_seconds_bucket{method="GET",route="/games/{id}",le="0.1"} 0
+http_request_duration_seconds_bucket{method="GET",route="/games/{id}",le="1"} 0
+http_request_duration_seconds_bucket{method="GET",route="/games/{id}",le="+Inf"} 1
+http_request_duration_seconds_sum{method="GET",route="/games/{id}"} 2
+http_request_duration_seconds_count{method="GET",route="/games/{id}"} 1
+`
+	if r := buf.String(); r != out {
+		t.Errorf("was expecting\n%v\nbut returns\n%v", out, r)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	c := NewCollector(DefaultBuckets)
+
+	router := chi.NewRouter()
+	router.Use(Middleware(c))
+	router.Get("/games/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	router.Get("/games", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	})
+
+	for _, url := range []string{"/games", "/games/1", "/games/2", "/nonexistent"} {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	var buf bytes.Buffer
+	c.Write(&buf)
+
+	for _, line := range []string{
+		`http_requests_total{method="GET",route="/games",status="200"} 1`,
+		`http_requests_total{method="GET",route="/games/{id}",status="404"} 2`,
+		`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`http_request_duration_seconds_count{method="GET",route="/games/{id}"} 2`,
+	} {
+		if !bytes.Contains(buf.Bytes(), []byte(line+"\n")) {
+			t.Errorf("was expecting the line\n%v\nin\n%v", line, buf.String())
+		}
+	}
+}
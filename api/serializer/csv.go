@@ -0,0 +1,74 @@
+package serializer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// csvHeader names the columns of the csv output, there is one row for every player in a game
+var csvHeader = []string{"game_id", "server", "total_kills", "player", "kills"}
+
+// csvRows generates the rows for a game, games without players have a row with only the game columns
+func csvRows(game *parser.Game) [][]string {
+	id := game.ID
+	totalKills := strconv.Itoa(game.TotalKills)
+
+	if len(game.Players) == 0 {
+		return [][]string{{id, game.Server, totalKills, "", ""}}
+	}
+
+	var rows [][]string
+	for _, p := range game.Players {
+		rows = append(rows, []string{id, game.Server, totalKills, p, strconv.Itoa(game.Kills[p])})
+	}
+	return rows
+}
+
+func writeCSV(games []*parser.Game) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	rows := [][]string{csvHeader}
+	for _, g := range games {
+		rows = append(rows, csvRows(g)...)
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type csvGameSerializer struct{}
+
+// NewCSVGameSerializer creates a new instance of GameSerializer for csv serialization
+func NewCSVGameSerializer() GameSerializer {
+	return &csvGameSerializer{}
+}
+
+func (s *csvGameSerializer) Serialize(game *parser.Game) ([]byte, error) {
+	b, err := writeCSV([]*parser.Game{game})
+	if err != nil {
+		return nil, fmt.Errorf("could not serialize game: %v", err)
+	}
+	return b, nil
+}
+
+type csvGamesSerializer struct{}
+
+// NewCSVGamesSerializer creates a new instance of GamesSerializer for csv serialization
+func NewCSVGamesSerializer() GamesSerializer {
+	return &csvGamesSerializer{}
+}
+
+func (s *csvGamesSerializer) Serialize(games []*parser.Game) ([]byte, error) {
+	b, err := writeCSV(games)
+	if err != nil {
+		return nil, fmt.Errorf("could not serialize games: %v", err)
+	}
+	return b, nil
+}
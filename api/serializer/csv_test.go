@@ -0,0 +1,103 @@
+package serializer
+
+import (
+	"testing"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+func TestCSVGameSerializer(t *testing.T) {
+	tt := []struct {
+		description string
+		in          *parser.Game
+		out         string
+	}{
+		{
+			description: "an empty game",
+			in: &parser.Game{
+				ID:         "1",
+				TotalKills: 0,
+				Players:    []string{},
+				Kills:      map[string]int{},
+			},
+			out: "game_id,server,total_kills,player,kills\n1,,0,,\n",
+		},
+		{
+			description: "a normal game",
+			in: &parser.Game{
+				ID:         "2",
+				TotalKills: 4,
+				Players:    []string{"player one", "player, two"},
+				Kills: map[string]int{
+					"player one":  1,
+					"player, two": 3,
+				},
+			},
+			out: "game_id,server,total_kills,player,kills\n2,,4,player one,1\n2,,4,\"player, two\",3\n",
+		},
+	}
+
+	s := NewCSVGameSerializer()
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			r, err := s.Serialize(tc.in)
+			if err != nil {
+				t.Errorf("an unexpected error occurred: %v", err)
+			}
+			if string(r) != tc.out {
+				t.Errorf("was expecting\n%v\nbut returns\n%v\n", tc.out, string(r))
+			}
+		})
+	}
+}
+
+func TestCSVGamesSerializer(t *testing.T) {
+	tt := []struct {
+		description string
+		in          []*parser.Game
+		out         string
+	}{
+		{
+			description: "an empty games list",
+			in:          []*parser.Game{},
+			out:         "game_id,server,total_kills,player,kills\n",
+		},
+		{
+			description: "a populated games list",
+			in: []*parser.Game{
+				{
+					ID:         "1",
+					TotalKills: 0,
+					Players:    []string{},
+					Kills:      map[string]int{},
+				},
+				{
+					ID:         "server-2",
+					Server:     "server",
+					TotalKills: 4,
+					Players:    []string{"player one", "player two"},
+					Kills: map[string]int{
+						"player one": 1,
+						"player two": 3,
+					},
+				},
+			},
+			out: "game_id,server,total_kills,player,kills\n1,,0,,\nserver-2,server,4,player one,1\nserver-2,server,4,player two,3\n",
+		},
+	}
+
+	s := NewCSVGamesSerializer()
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			r, err := s.Serialize(tc.in)
+			if err != nil {
+				t.Errorf("an unexpected error occurred: %v", err)
+			}
+			if string(r) != tc.out {
+				t.Errorf("was expecting\n%v\nbut returns\n%v\n", tc.out, string(r))
+			}
+		})
+	}
+}
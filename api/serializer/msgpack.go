@@ -0,0 +1,171 @@
+package serializer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// encodeMsgpack serializes v using the same shape of its json representation,
+// so every field added to the games is also available in msgpack
+func encodeMsgpack(v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var data interface{}
+	if err := d.Decode(&data); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := writeMsgpack(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeMsgpack writes the values generated by a json decoding in msgpack format
+func writeMsgpack(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+
+	case bool:
+		if v {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			writeMsgpackInt(buf, i)
+			return nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return err
+		}
+		buf.WriteByte(0xcb)
+		binary.Write(buf, binary.BigEndian, math.Float64bits(f))
+
+	case string:
+		n := len(v)
+		switch {
+		case n < 32:
+			buf.WriteByte(0xa0 | byte(n))
+		case n <= math.MaxUint8:
+			buf.Write([]byte{0xd9, byte(n)})
+		case n <= math.MaxUint16:
+			buf.WriteByte(0xda)
+			binary.Write(buf, binary.BigEndian, uint16(n))
+		default:
+			buf.WriteByte(0xdb)
+			binary.Write(buf, binary.BigEndian, uint32(n))
+		}
+		buf.WriteString(v)
+
+	case []interface{}:
+		writeMsgpackLength(buf, len(v), 0x90, 0xdc, 0xdd)
+		for _, e := range v {
+			if err := writeMsgpack(buf, e); err != nil {
+				return err
+			}
+		}
+
+	case map[string]interface{}:
+		// keys are sorted to generate always the same output
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		writeMsgpackLength(buf, len(v), 0x80, 0xde, 0xdf)
+		for _, k := range keys {
+			writeMsgpack(buf, k)
+			if err := writeMsgpack(buf, v[k]); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("unsupported type %T", v)
+	}
+
+	return nil
+}
+
+func writeMsgpackInt(buf *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0 && i < 128:
+		buf.WriteByte(byte(i))
+	case i < 0 && i >= -32:
+		buf.WriteByte(byte(int8(i)))
+	case i >= math.MinInt8 && i <= math.MaxInt8:
+		buf.Write([]byte{0xd0, byte(int8(i))})
+	case i >= math.MinInt16 && i <= math.MaxInt16:
+		buf.WriteByte(0xd1)
+		binary.Write(buf, binary.BigEndian, int16(i))
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		buf.WriteByte(0xd2)
+		binary.Write(buf, binary.BigEndian, int32(i))
+	default:
+		buf.WriteByte(0xd3)
+		binary.Write(buf, binary.BigEndian, i)
+	}
+}
+
+// writeMsgpackLength writes the header of arrays and maps, which has a fix, 16 and 32 bits variants
+func writeMsgpackLength(buf *bytes.Buffer, n int, fix, b16, b32 byte) {
+	switch {
+	case n < 16:
+		buf.WriteByte(fix | byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(b16)
+		binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(b32)
+		binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+}
+
+type msgpackGameSerializer struct{}
+
+// NewMsgpackGameSerializer creates a new instance of GameSerializer for msgpack serialization
+func NewMsgpackGameSerializer() GameSerializer {
+	return &msgpackGameSerializer{}
+}
+
+func (s *msgpackGameSerializer) Serialize(game *parser.Game) ([]byte, error) {
+	b, err := encodeMsgpack(game)
+	if err != nil {
+		return nil, fmt.Errorf("could not serialize game: %v", err)
+	}
+	return b, nil
+}
+
+type msgpackGamesSerializer struct{}
+
+// NewMsgpackGamesSerializer creates a new instance of GamesSerializer for msgpack serialization
+func NewMsgpackGamesSerializer() GamesSerializer {
+	return &msgpackGamesSerializer{}
+}
+
+func (s *msgpackGamesSerializer) Serialize(games []*parser.Game) ([]byte, error) {
+	b, err := encodeMsgpack(games)
+	if err != nil {
+		return nil, fmt.Errorf("could not serialize games: %v", err)
+	}
+	return b, nil
+}
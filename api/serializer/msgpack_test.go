@@ -0,0 +1,85 @@
+package serializer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+func TestWriteMsgpack(t *testing.T) {
+	tt := []struct {
+		description string
+		in          interface{}
+		out         string
+	}{
+		{"nil", nil, "\xc0"},
+		{"true", true, "\xc3"},
+		{"false", false, "\xc2"},
+		{"positive fixint", json.Number("7"), "\x07"},
+		{"negative fixint", json.Number("-7"), "\xf9"},
+		{"int8", json.Number("-100"), "\xd0\x9c"},
+		{"int16", json.Number("1000"), "\xd1\x03\xe8"},
+		{"int32", json.Number("100000"), "\xd2\x00\x01\x86\xa0"},
+		{"int64", json.Number("10000000000"), "\xd3\x00\x00\x00\x02\x54\x0b\xe4\x00"},
+		{"float", json.Number("1.5"), "\xcb\x3f\xf8\x00\x00\x00\x00\x00\x00"},
+		{"fixstr", "Zeh", "\xa3Zeh"},
+		{"str8", strings.Repeat("a", 40), "\xd9\x28" + strings.Repeat("a", 40)},
+		{"fixarray", []interface{}{"a", json.Number("1")}, "\x92\xa1a\x01"},
+		{"fixmap with sorted keys", map[string]interface{}{"b": true, "a": nil}, "\x82\xa1a\xc0\xa1b\xc3"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeMsgpack(&buf, tc.in); err != nil {
+				t.Errorf("an unexpected error occurred: %v", err)
+			}
+			if r := buf.String(); r != tc.out {
+				t.Errorf("was expecting %x, but returns %x", tc.out, r)
+			}
+		})
+	}
+}
+
+func TestMsgpackGameSerializer(t *testing.T) {
+	in := &parser.Game{
+		ID:         "2",
+		TotalKills: 4,
+		Players:    []string{"a"},
+		Kills: map[string]int{
+			"a": -7,
+		},
+	}
+	out := "\x84\xa2id\xa12\xa5kills\x81\xa1a\xf9\xa7players\x91\xa1a\xabtotal_kills\x04"
+
+	r, err := NewMsgpackGameSerializer().Serialize(in)
+	if err != nil {
+		t.Errorf("an unexpected error occurred: %v", err)
+	}
+	if string(r) != out {
+		t.Errorf("was expecting %x, but returns %x", out, r)
+	}
+}
+
+func TestMsgpackGamesSerializer(t *testing.T) {
+	in := []*parser.Game{
+		{
+			ID:         "1",
+			TotalKills: 0,
+			Players:    []string{},
+			Kills:      map[string]int{},
+		},
+	}
+	out := "\x91\x84\xa2id\xa11\xa5kills\x80\xa7players\x90\xabtotal_kills\x00"
+
+	r, err := NewMsgpackGamesSerializer().Serialize(in)
+	if err != nil {
+		t.Errorf("an unexpected error occurred: %v", err)
+	}
+	if string(r) != out {
+		t.Errorf("was expecting %x, but returns %x", out, r)
+	}
+}
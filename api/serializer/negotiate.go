@@ -0,0 +1,138 @@
+package serializer
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Format groups the serializers that generate one content type
+type Format struct {
+	Name        string
+	ContentType string
+	Game        GameSerializer
+	Games       GamesSerializer
+}
+
+// Reusable errors
+var (
+	ErrNotAcceptable = errors.New("could not serialize to any of the accepted formats")
+)
+
+// Formats lists the supported formats, the first is used when the client accepts anything
+var Formats = []*Format{
+	{
+		Name:        "json",
+		ContentType: "application/json",
+		Game:        NewJSONGameSerializer(),
+		Games:       NewJSONGamesSerializer(),
+	},
+	{
+		Name:        "csv",
+		ContentType: "text/csv",
+		Game:        NewCSVGameSerializer(),
+		Games:       NewCSVGamesSerializer(),
+	},
+	{
+		Name:        "xml",
+		ContentType: "application/xml",
+		Game:        NewXMLGameSerializer(),
+		Games:       NewXMLGamesSerializer(),
+	},
+	{
+		Name:        "msgpack",
+		ContentType: "application/msgpack",
+		Game:        NewMsgpackGameSerializer(),
+		Games:       NewMsgpackGamesSerializer(),
+	},
+}
+
+// aliases maps other media types commonly used for the supported formats
+var aliases = map[string]string{
+	"text/xml":              "application/xml",
+	"application/x-msgpack": "application/msgpack",
+	"application/csv":       "text/csv",
+}
+
+// Negotiate chooses the Format based in the Accept header,
+// the format name, when informed, takes precedence over the header
+func Negotiate(accept, format string) (*Format, error) {
+	if format != "" {
+		for _, f := range Formats {
+			if f.Name == format {
+				return f, nil
+			}
+		}
+		return nil, ErrNotAcceptable
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return Formats[0], nil
+	}
+
+	for _, mediaType := range acceptedMediaTypes(accept) {
+		if a, ok := aliases[mediaType]; ok {
+			mediaType = a
+		}
+		for _, f := range Formats {
+			if matchMediaType(mediaType, f.ContentType) {
+				return f, nil
+			}
+		}
+	}
+
+	return nil, ErrNotAcceptable
+}
+
+// acceptedMediaTypes parses an Accept header and returns the media types ordered by quality,
+// the media types with quality zero are discarded
+func acceptedMediaTypes(accept string) []string {
+	type mediaRange struct {
+		mediaType string
+		quality   float64
+	}
+
+	var rs []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+
+		r := mediaRange{
+			mediaType: strings.ToLower(strings.TrimSpace(params[0])),
+			quality:   1,
+		}
+		for _, p := range params[1:] {
+			p = strings.TrimSpace(p)
+			if strings.HasPrefix(p, "q=") {
+				if q, err := strconv.ParseFloat(p[2:], 64); err == nil {
+					r.quality = q
+				}
+			}
+		}
+
+		if r.mediaType != "" && r.quality > 0 {
+			rs = append(rs, r)
+		}
+	}
+
+	sort.SliceStable(rs, func(i, j int) bool {
+		return rs[i].quality > rs[j].quality
+	})
+
+	mediaTypes := make([]string, len(rs))
+	for i, r := range rs {
+		mediaTypes[i] = r.mediaType
+	}
+	return mediaTypes
+}
+
+// matchMediaType verify if the accepted media type, which can have wildcards, matches the content type
+func matchMediaType(accepted, contentType string) bool {
+	if accepted == "*/*" || accepted == contentType {
+		return true
+	}
+	if strings.HasSuffix(accepted, "/*") {
+		return strings.HasPrefix(contentType, strings.TrimSuffix(accepted, "*"))
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package serializer
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	type In struct {
+		accept string
+		format string
+	}
+	tt := []struct {
+		description string
+		in          In
+		out         string
+		err         error
+	}{
+		{"without preferences", In{"", ""}, "json", nil},
+		{"accept anything", In{"*/*", ""}, "json", nil},
+		{"accept csv", In{"text/csv", ""}, "csv", nil},
+		{"accept xml alias", In{"text/xml", ""}, "xml", nil},
+		{"accept msgpack alias", In{"application/x-msgpack", ""}, "msgpack", nil},
+		{"accept by quality", In{"application/json;q=0.5, application/xml", ""}, "xml", nil},
+		{"accept wildcard subtype", In{"text/*", ""}, "csv", nil},
+		{"skip unsupported types", In{"text/html, application/msgpack;q=0.9", ""}, "msgpack", nil},
+		{"skip refused types", In{"text/csv;q=0, */*;q=0.1", ""}, "json", nil},
+		{"format overrides accept", In{"application/json", "csv"}, "csv", nil},
+		{"unsupported accept", In{"text/html", ""}, "", ErrNotAcceptable},
+		{"unsupported format", In{"", "yaml"}, "", ErrNotAcceptable},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			f, err := Negotiate(tc.in.accept, tc.in.format)
+			if err != tc.err {
+				t.Errorf(`was expecting "%v" error, but returns "%v" error`, tc.err, err)
+			}
+			if f != nil && f.Name != tc.out {
+				t.Errorf("was expecting %s format, but returns %s", tc.out, f.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package serializer
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// xmlGame is the xml representation of a game, needed because xml does not support maps
+type xmlGame struct {
+	XMLName    xml.Name    `xml:"game"`
+	ID         string      `xml:"id,attr"`
+	Server     string      `xml:"server,attr,omitempty"`
+	TotalKills int         `xml:"total_kills,attr"`
+	Players    []xmlPlayer `xml:"players>player"`
+}
+
+type xmlPlayer struct {
+	Name  string `xml:"name,attr"`
+	Kills int    `xml:"kills,attr"`
+}
+
+type xmlGames struct {
+	XMLName xml.Name   `xml:"games"`
+	Games   []*xmlGame `xml:"game"`
+}
+
+func newXMLGame(game *parser.Game) *xmlGame {
+	g := &xmlGame{
+		ID:         game.ID,
+		Server:     game.Server,
+		TotalKills: game.TotalKills,
+	}
+	for _, p := range game.Players {
+		g.Players = append(g.Players, xmlPlayer{p, game.Kills[p]})
+	}
+	return g
+}
+
+type xmlGameSerializer struct{}
+
+// NewXMLGameSerializer creates a new instance of GameSerializer for xml serialization
+func NewXMLGameSerializer() GameSerializer {
+	return &xmlGameSerializer{}
+}
+
+func (s *xmlGameSerializer) Serialize(game *parser.Game) ([]byte, error) {
+	b, err := xml.Marshal(newXMLGame(game))
+	if err != nil {
+		return nil, fmt.Errorf("could not serialize game: %v", err)
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+type xmlGamesSerializer struct{}
+
+// NewXMLGamesSerializer creates a new instance of GamesSerializer for xml serialization
+func NewXMLGamesSerializer() GamesSerializer {
+	return &xmlGamesSerializer{}
+}
+
+func (s *xmlGamesSerializer) Serialize(games []*parser.Game) ([]byte, error) {
+	gs := &xmlGames{}
+	for _, g := range games {
+		gs.Games = append(gs.Games, newXMLGame(g))
+	}
+
+	b, err := xml.Marshal(gs)
+	if err != nil {
+		return nil, fmt.Errorf("could not serialize games: %v", err)
+	}
+	return append([]byte(xml.Header), b...), nil
+}
@@ -0,0 +1,106 @@
+package serializer
+
+import (
+	"testing"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+func TestXMLGameSerializer(t *testing.T) {
+	tt := []struct {
+		description string
+		in          *parser.Game
+		out         string
+	}{
+		{
+			description: "an empty game",
+			in: &parser.Game{
+				ID:         "1",
+				TotalKills: 0,
+				Players:    []string{},
+				Kills:      map[string]int{},
+			},
+			out: `<?xml version="1.0" encoding="UTF-8"?>
+<game id="1" total_kills="0"><players></players></game>`,
+		},
+		{
+			description: "a normal game",
+			in: &parser.Game{
+				ID:         "2",
+				TotalKills: 4,
+				Players:    []string{"player one", "<player two>"},
+				Kills: map[string]int{
+					"player one":   1,
+					"<player two>": 3,
+				},
+			},
+			out: `<?xml version="1.0" encoding="UTF-8"?>
+<game id="2" total_kills="4"><players><player name="player one" kills="1"></player><player name="&lt;player two&gt;" kills="3"></player></players></game>`,
+		},
+	}
+
+	s := NewXMLGameSerializer()
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			r, err := s.Serialize(tc.in)
+			if err != nil {
+				t.Errorf("an unexpected error occurred: %v", err)
+			}
+			if string(r) != tc.out {
+				t.Errorf("was expecting\n%v\nbut returns\n%v\n", tc.out, string(r))
+			}
+		})
+	}
+}
+
+func TestXMLGamesSerializer(t *testing.T) {
+	tt := []struct {
+		description string
+		in          []*parser.Game
+		out         string
+	}{
+		{
+			description: "an empty games list",
+			in:          []*parser.Game{},
+			out: `<?xml version="1.0" encoding="UTF-8"?>
+<games></games>`,
+		},
+		{
+			description: "a populated games list",
+			in: []*parser.Game{
+				{
+					ID:         "1",
+					TotalKills: 0,
+					Players:    []string{},
+					Kills:      map[string]int{},
+				},
+				{
+					ID:         "server-2",
+					Server:     "server",
+					TotalKills: 4,
+					Players:    []string{"player one"},
+					Kills: map[string]int{
+						"player one": 1,
+					},
+				},
+			},
+			out: `<?xml version="1.0" encoding="UTF-8"?>
+<games><game id="1" total_kills="0"><players></players></game><game id="server-2" server="server" total_kills="4"><players><player name="player one" kills="1"></player></players></game></games>`,
+		},
+	}
+
+	s := NewXMLGamesSerializer()
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			r, err := s.Serialize(tc.in)
+			if err != nil {
+				t.Errorf("an unexpected error occurred: %v", err)
+			}
+			if string(r) != tc.out {
+				t.Errorf("was expecting\n%v\nbut returns\n%v\n", tc.out, string(r))
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package service
 
 import (
+	"github.com/bgildson/enext-challenge/alias"
 	"github.com/bgildson/enext-challenge/api/repository"
+	"github.com/bgildson/enext-challenge/exclude"
 	"github.com/bgildson/enext-challenge/parser"
 )
 
@@ -29,3 +31,61 @@ func (s *gamesService) List() ([]*parser.Game, error) {
 func (s *gamesService) Find(id string) (*parser.Game, error) {
 	return s.repo.GetByID(id)
 }
+
+type aliasedGamesService struct {
+	s       GamesService
+	aliases alias.Aliases
+}
+
+// NewAliasedGamesService creates a new instance of GamesService which names the players by their identities
+func NewAliasedGamesService(s GamesService, aliases alias.Aliases) GamesService {
+	return &aliasedGamesService{
+		s:       s,
+		aliases: aliases,
+	}
+}
+
+func (s *aliasedGamesService) List() ([]*parser.Game, error) {
+	games, err := s.s.List()
+	if err != nil {
+		return nil, err
+	}
+	return s.aliases.Games(games), nil
+}
+
+func (s *aliasedGamesService) Find(id string) (*parser.Game, error) {
+	game, err := s.s.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.aliases.Game(game), nil
+}
+
+type excludingGamesService struct {
+	s          GamesService
+	exclusions *exclude.Exclusions
+}
+
+// NewExcludingGamesService creates a new instance of GamesService which leaves the excluded players out of the games
+func NewExcludingGamesService(s GamesService, exclusions *exclude.Exclusions) GamesService {
+	return &excludingGamesService{
+		s:          s,
+		exclusions: exclusions,
+	}
+}
+
+func (s *excludingGamesService) List() ([]*parser.Game, error) {
+	games, err := s.s.List()
+	if err != nil {
+		return nil, err
+	}
+	return s.exclusions.Games(games), nil
+}
+
+func (s *excludingGamesService) Find(id string) (*parser.Game, error) {
+	game, err := s.s.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.exclusions.Game(game), nil
+}
@@ -5,7 +5,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/bgildson/enext-challenge/alias"
 	"github.com/bgildson/enext-challenge/api/repository"
+	"github.com/bgildson/enext-challenge/exclude"
 	"github.com/bgildson/enext-challenge/parser"
 )
 
@@ -103,3 +105,143 @@ func TestGamesService(t *testing.T) {
 		})
 	})
 }
+
+func TestAliasedGamesService(t *testing.T) {
+	game := &parser.Game{
+		ID:         "1",
+		TotalKills: 3,
+		Players:    []string{"Isgalamido", "Isga", "Mocinha"},
+		Kills: map[string]int{
+			"Isgalamido": 1,
+			"Isga":       2,
+			"Mocinha":    0,
+		},
+	}
+	expected := &parser.Game{
+		ID:         "1",
+		TotalKills: 3,
+		Players:    []string{"Isgalamido", "Mocinha"},
+		Kills: map[string]int{
+			"Isgalamido": 3,
+			"Mocinha":    0,
+		},
+	}
+	aliases := alias.Aliases{"Isga": "Isgalamido"}
+	serviceSuccess := NewAliasedGamesService(NewMockGamesService(
+		func() ([]*parser.Game, error) {
+			return []*parser.Game{game}, nil
+		},
+		func(id string) (*parser.Game, error) {
+			return game, nil
+		},
+	), aliases)
+	serviceFailure := NewAliasedGamesService(NewMockGamesService(
+		func() ([]*parser.Game, error) {
+			return nil, fmt.Errorf("occur an error")
+		},
+		func(id string) (*parser.Game, error) {
+			return nil, fmt.Errorf("occur an error")
+		},
+	), aliases)
+
+	t.Run("List", func(t *testing.T) {
+		r, err := serviceSuccess.List()
+		if err != nil {
+			t.Errorf("an unexpected error occurred: %v", err)
+		}
+		if !reflect.DeepEqual(r, []*parser.Game{expected}) {
+			t.Errorf("was expecting\n%#v\nbut returns\n%#v\n", []*parser.Game{expected}, r)
+		}
+
+		r, err = serviceFailure.List()
+		if err == nil || r != nil {
+			t.Errorf("was expecting an error and no result, but returns %v and %v", err, r)
+		}
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		r, err := serviceSuccess.Find(game.ID)
+		if err != nil {
+			t.Errorf("an unexpected error occurred: %v", err)
+		}
+		if !reflect.DeepEqual(r, expected) {
+			t.Errorf("was expecting\n%#v\nbut returns\n%#v\n", expected, r)
+		}
+
+		r, err = serviceFailure.Find(game.ID)
+		if err == nil || r != nil {
+			t.Errorf("was expecting an error and no result, but returns %v and %v", err, r)
+		}
+	})
+}
+
+func TestExcludingGamesService(t *testing.T) {
+	game := &parser.Game{
+		ID:         "1",
+		TotalKills: 3,
+		Players:    []string{"Isgalamido", "Sarge"},
+		Kills: map[string]int{
+			"Isgalamido": 1,
+			"Sarge":      2,
+		},
+		Bots: map[string]string{"Sarge": "sarge"},
+	}
+	expected := &parser.Game{
+		ID:         "1",
+		TotalKills: 3,
+		Players:    []string{"Isgalamido"},
+		Kills: map[string]int{
+			"Isgalamido": 1,
+		},
+	}
+	exclusions, err := exclude.New(exclude.Config{Bots: true})
+	if err != nil {
+		t.Fatalf("an unexpected error occurred: %v", err)
+	}
+	serviceSuccess := NewExcludingGamesService(NewMockGamesService(
+		func() ([]*parser.Game, error) {
+			return []*parser.Game{game}, nil
+		},
+		func(id string) (*parser.Game, error) {
+			return game, nil
+		},
+	), exclusions)
+	serviceFailure := NewExcludingGamesService(NewMockGamesService(
+		func() ([]*parser.Game, error) {
+			return nil, fmt.Errorf("occur an error")
+		},
+		func(id string) (*parser.Game, error) {
+			return nil, fmt.Errorf("occur an error")
+		},
+	), exclusions)
+
+	t.Run("List", func(t *testing.T) {
+		r, err := serviceSuccess.List()
+		if err != nil {
+			t.Errorf("an unexpected error occurred: %v", err)
+		}
+		if !reflect.DeepEqual(r, []*parser.Game{expected}) {
+			t.Errorf("was expecting\n%#v\nbut returns\n%#v\n", []*parser.Game{expected}, r)
+		}
+
+		r, err = serviceFailure.List()
+		if err == nil || r != nil {
+			t.Errorf("was expecting an error and no result, but returns %v and %v", err, r)
+		}
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		r, err := serviceSuccess.Find(game.ID)
+		if err != nil {
+			t.Errorf("an unexpected error occurred: %v", err)
+		}
+		if !reflect.DeepEqual(r, expected) {
+			t.Errorf("was expecting\n%#v\nbut returns\n%#v\n", expected, r)
+		}
+
+		r, err = serviceFailure.Find(game.ID)
+		if err == nil || r != nil {
+			t.Errorf("was expecting an error and no result, but returns %v and %v", err, r)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/bgildson/enext-challenge/alias"
+	"github.com/bgildson/enext-challenge/ingest"
+	"github.com/bgildson/enext-challenge/parser"
+	"github.com/bgildson/enext-challenge/schema"
+)
+
+func main() {
+	gamesJSONPath := flag.String("games-json-path", "./games.json", "specify the json path of the parsed games.log")
+	logPath := flag.String("log", "", "specify the games log to find the players renamed by the same client, optional")
+	maxDistance := flag.Int("distance", alias.DefaultDistance, "specify the maximum edit distance between the names of the same player")
+	out := flag.String("out", "", "specify the path to write the suggested aliases json, when empty writes to the stdout")
+	flag.Parse()
+
+	games, err := schema.ReadGames(*gamesJSONPath)
+	if err != nil {
+		log.Fatalf("could not load games from source: %v", err)
+	}
+
+	var renames []*parser.Event
+	if *logPath != "" {
+		renames, err = readRenames(*logPath)
+		if err != nil {
+			log.Fatalf("could not read the renames from the log: %v", err)
+		}
+	}
+
+	suggestions := alias.Suggest(games, renames, *maxDistance)
+	for _, s := range suggestions {
+		log.Printf("%q looks like %q (%s)", s.Alias, s.Identity, s.Reason)
+	}
+
+	b, err := json.MarshalIndent(alias.Identities(suggestions), "", "  ")
+	if err != nil {
+		log.Fatalf("could not generate the aliases: %v", err)
+	}
+	b = append(b, '\n')
+
+	if *out == "" {
+		os.Stdout.Write(b)
+		return
+	}
+	if err := ioutil.WriteFile(*out, b, 0644); err != nil {
+		log.Fatalf("could not write the aliases: %v", err)
+	}
+}
+
+// readRenames collects the rename events of the log
+func readRenames(path string) ([]*parser.Event, error) {
+	f, err := ingest.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var renames []*parser.Event
+	p := parser.NewEventParser()
+	r := bufio.NewReader(f)
+	for {
+		s, err := r.ReadString('\n')
+		for _, e := range p.Parse(s) {
+			if e.Type == parser.EventRename {
+				renames = append(renames, e)
+			}
+		}
+		if err == io.EOF {
+			return renames, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
@@ -1,50 +1,201 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
 
+	"github.com/bgildson/enext-challenge/alias"
 	"github.com/bgildson/enext-challenge/api/database"
 	"github.com/bgildson/enext-challenge/api/handler"
+	"github.com/bgildson/enext-challenge/api/live"
+	"github.com/bgildson/enext-challenge/api/metrics"
 	"github.com/bgildson/enext-challenge/api/repository"
 	"github.com/bgildson/enext-challenge/api/service"
+	"github.com/bgildson/enext-challenge/exclude"
 )
 
+// envPrefix prefixes the environment variables that configure the api flags
+const envPrefix = "API_"
+
+// envName generates the environment variable name for a flag, like "games-json-path" to "API_GAMES_JSON_PATH"
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// parseFlags parses the command line flags, the flags not informed in the command line
+// take their values from the environment variables, when defined
+func parseFlags() {
+	flag.VisitAll(func(f *flag.Flag) {
+		f.Usage = fmt.Sprintf("%s (env %s)", f.Usage, envName(f.Name))
+	})
+	flag.Parse()
+
+	informed := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) {
+		informed[f.Name] = true
+	})
+
+	flag.VisitAll(func(f *flag.Flag) {
+		v, ok := os.LookupEnv(envName(f.Name))
+		if !ok || informed[f.Name] {
+			return
+		}
+		if err := f.Value.Set(v); err != nil {
+			log.Fatalf("invalid value %q for %s: %v", v, envName(f.Name), err)
+		}
+	})
+}
+
+// timeout bounds how long the handlers take to reply, answering 503 when they take longer
+func timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+		return http.TimeoutHandler(next, d, `{"message":"request timed out"}`)
+	}
+}
+
 func main() {
 	// parse params to obtain api configurations
 	gamesJSONPath := flag.String("games-json-path", "./games.json", "should inform the path for the games json file")
+	loadRetry := flag.Duration("load-retry", 5*time.Second, "how long to wait before retrying to load the games json file when it could not be loaded")
+	host := flag.String("host", "", "indicates which address the api should bind, when empty binds every interface")
 	port := flag.Int64("port", 8080, "indicates which port the api should listen")
-	flag.Parse()
+	readTimeout := flag.Duration("read-timeout", 5*time.Second, "maximum duration for reading an entire request")
+	readHeaderTimeout := flag.Duration("read-header-timeout", 5*time.Second, "maximum duration for reading the request headers")
+	writeTimeout := flag.Duration("write-timeout", 0, "maximum duration before timing out the response writes, also limits the /live streams, 0 disables it")
+	handlerTimeout := flag.Duration("handler-timeout", 10*time.Second, "maximum duration to reply the requests, except the /live streams, 0 disables it")
+	idleTimeout := flag.Duration("idle-timeout", 60*time.Second, "maximum duration to wait for the next request when keep-alives are enabled")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 15*time.Second, "maximum duration to wait the in-flight requests when shutting down")
+	liveLogPath := flag.String("live-log", "", "path to the log followed to stream live events, when empty /live is disabled")
+	liveBuffer := flag.Int("live-buffer", 64, "how many events a live client can hold before being dropped")
+	aliasesPath := flag.String("aliases", "", "path to the json or yaml file mapping the player names to their identities")
+	excludePath := flag.String("exclude", "", "path to the json file with the players left out of the rankings")
+	parseFlags()
+
+	// cancelled when the process receives a termination signal
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		stop()
+	}()
 
 	// bind app layers
-	db, err := database.NewJSONDatabase(*gamesJSONPath)
-	if err != nil {
-		log.Fatalf("could not create the database: %v", err)
-	}
+	// the api serves while the games are loaded, but is ready only after them
+	db := database.NewLoadingJSONDatabase(ctx, *gamesJSONPath, *loadRetry)
 	r := repository.NewJSONGamesRepository(db)
 	s := service.NewGamesService(r)
+	if *aliasesPath != "" {
+		aliases, err := alias.Load(*aliasesPath)
+		if err != nil {
+			log.Fatalf("could not load the aliases: %v", err)
+		}
+		s = service.NewAliasedGamesService(s, aliases)
+	}
 	h := handler.NewGamesHandler(s)
 
+	// the excluded players are left out only of the rankings
+	rs := s
+	if *excludePath != "" {
+		exclusions, err := exclude.Load(*excludePath)
+		if err != nil {
+			log.Fatalf("could not load the exclusions: %v", err)
+		}
+		rs = service.NewExcludingGamesService(s, exclusions)
+	}
+
 	// generate http router
 	router := chi.NewRouter()
 
-	// add minimum middlewares to log requests and recover from panics
+	// add minimum middlewares to log requests, collect metrics and recover from panics
+	collector := metrics.NewCollector(metrics.DefaultBuckets)
 	router.Use(middleware.Logger)
+	router.Use(metrics.Middleware(collector))
 	router.Use(middleware.Recoverer)
 
-	// bind handlers
-	router.Get("/games", h.GetAll)
-	router.Get("/games/{id}", h.GetOne)
+	// bind handlers, every reply but the live streams is bounded by the handler timeout
+	router.Group(func(router chi.Router) {
+		router.Use(timeout(*handlerTimeout))
+
+		router.Get("/games", h.GetAll)
+		router.Get("/games/{id}", h.GetOne)
+		router.Get("/games/{id}/achievements", h.GetAchievements)
+		router.Get("/games/{id}/timeline", h.GetTimeline)
+
+		rh := handler.NewRankingHandler(rs)
+		router.Get("/teams", rh.GetTeams)
+		router.Get("/weapons", rh.GetWeapons)
+		router.Get("/ranking", rh.GetRanking)
+		router.Get("/ranking.svg", rh.GetRankingSVG)
+		router.Get("/players/{name}/weapons", rh.GetPlayerWeapons)
+
+		// bind operational handlers
+		hh := handler.NewHealthHandler(s)
+		router.Get("/healthz", hh.Healthz)
+		router.Get("/readyz", hh.Readyz)
+		router.Get("/metrics", handler.NewMetricsHandler(collector, s).Metrics)
+	})
+
+	// stream live events when a log to follow was informed
+	liveCtx, stopLive := context.WithCancel(ctx)
+	defer stopLive()
+	if *liveLogPath != "" {
+		hub := live.NewHub(*liveBuffer)
+		go func() {
+			src := live.NewLogSource(*liveLogPath, time.Second)
+			if err := hub.Run(liveCtx, src); err != nil && err != context.Canceled {
+				log.Printf("live events stopped: %v", err)
+			}
+		}()
+		router.Get("/live", handler.NewLiveHandler(hub).Stream)
+	}
+
+	srv := &http.Server{
+		Addr:              net.JoinHostPort(*host, fmt.Sprint(*port)),
+		Handler:           router,
+		ReadTimeout:       *readTimeout,
+		ReadHeaderTimeout: *readHeaderTimeout,
+		WriteTimeout:      *writeTimeout,
+		IdleTimeout:       *idleTimeout,
+	}
+
+	// the live streams never become idle, so they are closed as soon as the shutdown starts
+	srv.RegisterOnShutdown(stopLive)
 
 	// serve api
-	addr := fmt.Sprintf(":%d", *port)
-	fmt.Println("Running api on", addr)
-	if err := http.ListenAndServe(addr, router); err != nil {
+	errs := make(chan error, 1)
+	go func() {
+		fmt.Println("Running api on", srv.Addr)
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
 		log.Fatal(err)
+	case <-ctx.Done():
+	}
+
+	// stop accepting new requests and wait the in-flight ones
+	fmt.Println("Shutting down api")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Fatalf("could not shutdown gracefully: %v", err)
 	}
 }
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/bgildson/enext-challenge/loggen"
+)
+
+func main() {
+	outPath := flag.String("out", "", "path to save the generated log, when empty the log is written to the standard output")
+	seed := flag.Int64("seed", loggen.DefaultConfig.Seed, "random seed, the same seed generates the same log")
+	games := flag.Int("games", loggen.DefaultConfig.Games, "number of games")
+	players := flag.Int("players", loggen.DefaultConfig.Players, "number of players in every game")
+	kills := flag.Int("kills", loggen.DefaultConfig.Kills, "number of kills in every game")
+	renames := flag.Int("renames", 0, "number of times the players change their names in every game")
+	weapons := flag.String("weapons", "", "comma separated means of death used by the players, like MOD_ROCKET,MOD_RAILGUN, all of them when empty")
+	abnormal := flag.Float64("abnormal", 0, "probability, from 0 to 1, of a game to end without a ShutdownGame")
+	flag.Parse()
+
+	c := loggen.Config{
+		Seed:     *seed,
+		Games:    *games,
+		Players:  *players,
+		Kills:    *kills,
+		Renames:  *renames,
+		Abnormal: *abnormal,
+	}
+	if *weapons != "" {
+		c.Weapons = strings.Split(*weapons, ",")
+	}
+	for _, w := range c.Weapons {
+		if !isWeapon(w) {
+			log.Fatalf("unknown weapon %q, should be one of %s", w, strings.Join(loggen.Weapons, ","))
+		}
+	}
+	if c.Abnormal < 0 || c.Abnormal > 1 {
+		log.Fatalf("invalid abnormal probability %v, should be between 0 and 1", c.Abnormal)
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("could not create the log file: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	w := bufio.NewWriter(out)
+	for _, l := range loggen.Lines(loggen.Generate(c)) {
+		w.WriteString(l)
+		w.WriteString("\n")
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatalf("could not write the log: %v", err)
+	}
+}
+
+func isWeapon(name string) bool {
+	for _, w := range loggen.Weapons {
+		if w == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/bgildson/enext-challenge/schema"
+)
+
+func main() {
+	inPath := flag.String("in", "./games.json", "path to the games json to upgrade")
+	outPath := flag.String("out", "", "path to save the upgraded games json, when empty the input is overwritten")
+	logPath := flag.String("log", "", "path to the source log, used to fill the checksum of legacy files")
+	flag.Parse()
+
+	if *outPath == "" {
+		*outPath = *inPath
+	}
+
+	doc, err := schema.ReadFile(*inPath)
+	if err != nil {
+		log.Fatalf("could not read the games json: %v", err)
+	}
+
+	if doc.SchemaVersion == schema.Version && *outPath == *inPath {
+		fmt.Printf("%s is already in schema version %d\n", *inPath, schema.Version)
+		return
+	}
+
+	// legacy files do not know which log generated them
+	if *logPath != "" && len(doc.Sources) == 0 {
+		f, err := os.Open(*logPath)
+		if err != nil {
+			log.Fatalf("could not read the log file: %v", err)
+		}
+		sum, err := schema.Checksum(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("could not calculate the log checksum: %v", err)
+		}
+		doc.Sources = append(doc.Sources, &schema.Source{Path: *logPath, SHA256: sum})
+	}
+
+	from := doc.SchemaVersion
+	doc.Upgrade()
+
+	if err := schema.WriteFile(*outPath, doc); err != nil {
+		log.Fatalf("could not write the upgraded games json: %v", err)
+	}
+
+	fmt.Printf("upgraded %s from schema version %d to %d\n", *outPath, from, doc.SchemaVersion)
+}
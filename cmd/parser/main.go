@@ -1,51 +1,115 @@
 package main
 
 import (
-	"bufio"
-	"encoding/json"
 	"flag"
-	"io/ioutil"
 	"log"
-	"os"
+	"runtime"
+	"strings"
+	"time"
 
+	"github.com/bgildson/enext-challenge/ingest"
 	"github.com/bgildson/enext-challenge/parser"
+	"github.com/bgildson/enext-challenge/schema"
 )
 
+// logsFlag collects the logs informed many times in the command line
+type logsFlag []string
+
+func (f *logsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *logsFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
 func main() {
-	logPath := flag.String("log", "./games.log", "path to the log file")
+	var logPaths logsFlag
+	flag.Var(&logPaths, "log", "path to the log file, a directory or a glob, optionally labeled like server=./games.log, can be repeated (default ./games.log)")
 	outPath := flag.String("out", "./games.json", "path to save processed log")
+	idStrategy := flag.String("ids", "sequential", "how to identify the games, sequential numbers or content, derived from the game beginning to keep the same ids across re-runs")
+	checkpointPath := flag.String("checkpoint", "", "path to the checkpoint file, when informed only the content appended to the logs since the last run is parsed")
+	workers := flag.Int("workers", runtime.NumCPU(), "how many games are processed concurrently, 1 to process them sequentially")
+	strict := flag.Bool("strict", false, "fails when a log has lines not understood or out of sequence, without writing the output")
 	flag.Parse()
 
-	// open log file
-	f, err := os.Open(*logPath)
+	ids := ingest.IDStrategy(*idStrategy)
+	if ids != ingest.SequentialIDs && ids != ingest.ContentIDs {
+		log.Fatalf("unknown ids strategy %q, should be sequential or content", *idStrategy)
+	}
+
+	// logs can also be informed as arguments
+	logPaths = append(logPaths, flag.Args()...)
+	if len(logPaths) == 0 {
+		logPaths = append(logPaths, "./games.log")
+	}
+
+	logs, err := ingest.Expand(logPaths)
 	if err != nil {
-		log.Fatalf("could not read the log file: %v", err)
+		log.Fatalf("could not find the log files: %v", err)
 	}
 
-	// capture log lines
-	lines := []string{}
-	s := bufio.NewScanner(f)
-	for s.Scan() {
-		lines = append(lines, s.Text())
+	// a single log without an explicit label keeps the sequential ids
+	tag := len(logs) > 1 || logs[0].Labeled
+
+	// resume from the checkpoints, which are only valid with the output they generated
+	cps := ingest.Checkpoints{}
+	doc := schema.NewDocument(nil)
+	if *checkpointPath != "" {
+		cps, err = ingest.ReadCheckpoints(*checkpointPath)
+		if err != nil {
+			log.Fatalf("could not read the checkpoints: %v", err)
+		}
+		if len(cps) > 0 {
+			doc, err = schema.ReadFile(*outPath)
+			if err != nil {
+				log.Printf("could not read the previous output, parsing from the beginning: %v", err)
+				cps = ingest.Checkpoints{}
+				doc = schema.NewDocument(nil)
+			}
+		}
 	}
 
-	// process log and generate games for every log
-	games := parser.ProcessLines(lines)
+	var diags parser.Diagnostics
+	for _, l := range logs {
+		label := ""
+		if tag {
+			label = l.Label
+		}
+
+		// process the log content not parsed yet
+		inc, err := ingest.ParseIncrement(l, ingest.Options{Server: label, IDs: ids, Workers: *workers, Resumable: *checkpointPath != ""}, cps[l.Path])
+		if err != nil {
+			log.Fatalf("could not parse the log file: %v", err)
+		}
 
-	// generate a map to add "ids" for every game
-	gamesMap := map[string]*parser.Game{}
-	for _, game := range games {
-		gamesMap[game.ID] = game
+		diags = append(diags, inc.Diagnostics...)
+		doc.AddGames(inc.Replaces, inc.Games)
+		doc.SetSource(&schema.Source{Label: label, Path: l.Path, SHA256: inc.Checksum})
+		cps[l.Path] = inc.Checkpoint
 	}
 
-	// serialize games map
-	gamesJSON, err := json.MarshalIndent(gamesMap, "", "  ")
-	if err != nil {
-		log.Fatalf("could not serialize games: %v", err)
+	if len(diags) > 0 {
+		if *strict {
+			for _, d := range diags {
+				log.Print(d)
+			}
+			log.Fatal(diags.Summary())
+		}
+		log.Print(diags.Summary())
 	}
 
 	// write serialized data
-	if err := ioutil.WriteFile(*outPath, gamesJSON, 0644); err != nil {
+	doc.Upgrade()
+	doc.GeneratedAt = time.Now().UTC()
+	if err := schema.WriteFile(*outPath, doc); err != nil {
 		log.Fatalf("could not write serialized games: %v", err)
 	}
+
+	if *checkpointPath != "" {
+		if err := ingest.WriteCheckpoints(*checkpointPath, cps); err != nil {
+			log.Fatalf("could not write the checkpoints: %v", err)
+		}
+	}
 }
@@ -1,56 +1,157 @@
 package main
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
-	"sort"
-	"strconv"
+	"os"
 
+	"github.com/bgildson/enext-challenge/alias"
+	"github.com/bgildson/enext-challenge/exclude"
 	"github.com/bgildson/enext-challenge/parser"
 	"github.com/bgildson/enext-challenge/report"
+	"github.com/bgildson/enext-challenge/schema"
 )
 
 func main() {
 	gamesJSONPath := flag.String("games-json-path", "./games.json", "specify the json path of the parsed games.log")
 	general := flag.Bool("general", true, "specify if the report should be general")
+	teams := flag.Bool("teams", false, "specify if the report should rank the teams of the team games")
+	mode := flag.String("mode", "", "specify the report mode, general, games, teams, weapons, achievements or timeline, overriding the general and teams flags")
+	gameID := flag.String("game", "", "specify the game id of the timeline, every game when empty")
+	format := flag.String("format", "text", "specify the report format, text, csv for the timeline or svg for the general ranking and the timeline")
+	streak := flag.Int("streak", report.DefaultAchievementConfig.Streak, "specify how many kills without dying are a streak, for the achievements")
+	multiKill := flag.Int("multi-kill", report.DefaultAchievementConfig.MultiKill, "specify how many kills inside the window are a multi-kill, for the achievements")
+	window := flag.Duration("window", report.DefaultAchievementConfig.Window, "specify the time between the kills of a multi-kill, for the achievements")
+	ranking := flag.String("ranking", report.DefaultRankingConfig.Mode, "specify how the general report ranks the players, total points, average points per game or points per minute played, total, average or per_minute")
+	minGames := flag.Int("min-games", report.DefaultRankingConfig.MinGames, "specify how many games a player needs to be ranked in the general report")
+	ctfBonus := flag.Bool("ctf-bonus", report.DefaultRankingConfig.CTFBonus, "specify if the flag captures, returns and carrier kills score in the general report, besides the kills")
+	aliasesPath := flag.String("aliases", "", "specify the json or yaml file mapping the player names to their identities")
+	excludePath := flag.String("exclude", "", "specify the json file with the players left out of the reports")
 	flag.Parse()
 
-	// try read source games
-	data, err := ioutil.ReadFile(*gamesJSONPath)
-	if err != nil {
-		log.Fatalf("could not read source file: %v", err)
+	// the mode flag replaces the general and teams flags
+	if *mode == "" {
+		*mode = "games"
+		if *teams {
+			*mode = "teams"
+		} else if *general {
+			*mode = "general"
+		}
 	}
 
-	var gamesMap map[string]*parser.Game
+	// the reports other than text are available only in some modes
+	formats := map[string][]string{
+		"csv": {"timeline"},
+		"svg": {"general", "timeline"},
+	}
+	if modes, ok := formats[*format]; *format != "text" && !contains(modes, *mode) {
+		if !ok {
+			log.Fatalf("unknown report format %q", *format)
+		}
+		log.Fatalf("the %s format is not available for the %s mode", *format, *mode)
+	}
 
-	// deserialize games from source
-	err = json.Unmarshal(data, &gamesMap)
+	// the general ranking can compare the players by other measures than the total points
+	rankingConfig := report.RankingConfig{Mode: *ranking, MinGames: *minGames, CTFBonus: *ctfBonus}
+	if err := rankingConfig.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	if rankingConfig != report.DefaultRankingConfig && *mode == "general" && *format == "svg" {
+		log.Fatalf("the svg format is available only for the total ranking of every player")
+	}
+
+	games, err := schema.ReadGames(*gamesJSONPath)
 	if err != nil {
 		log.Fatalf("could not load games from source: %v", err)
 	}
 
-	// parse map for list
-	var games []*parser.Game
-	for _, g := range gamesMap {
-		games = append(games, g)
+	// the names of the same player are reported as one identity
+	if *aliasesPath != "" {
+		aliases, err := alias.Load(*aliasesPath)
+		if err != nil {
+			log.Fatalf("could not load the aliases: %v", err)
+		}
+		games = aliases.Games(games)
 	}
 
-	// order ranking by id
-	sort.Slice(games, func(i, j int) bool {
-		x, _ := strconv.Atoi(games[i].ID)
-		y, _ := strconv.Atoi(games[j].ID)
-		return x < y
-	})
+	// the excluded players are left out of every report, their kills still count in the total kills
+	all := games
+	if *excludePath != "" {
+		exclusions, err := exclude.Load(*excludePath)
+		if err != nil {
+			log.Fatalf("could not load the exclusions: %v", err)
+		}
+		games = exclusions.Games(games)
+	}
 
-	// print result, grouped or not based in general flag
-	if *general {
-		fmt.Println(report.ForGames(games))
-	} else {
+	// print result based in the mode
+	switch *mode {
+	case "general":
+		if *format == "svg" {
+			r := report.NewRanking()
+			for _, g := range games {
+				r.AddGame(g)
+			}
+			fmt.Print(report.RankingSVG(r))
+		} else {
+			fmt.Println(report.ForGeneral(games, rankingConfig))
+		}
+	case "games":
 		for _, g := range games {
 			fmt.Printf("%s\n\n", report.ForGame(g))
 		}
+	case "teams":
+		fmt.Println(report.ForTeams(games))
+	case "weapons":
+		fmt.Println(report.ForWeaponsWithTotal(games, report.WeaponKills(all)))
+	case "achievements":
+		fmt.Println(report.ForAchievements(games, report.AchievementConfig{
+			Streak:    *streak,
+			MultiKill: *multiKill,
+			Window:    *window,
+		}))
+	case "timeline":
+		if *gameID != "" {
+			games = findGame(games, *gameID)
+		}
+		switch *format {
+		case "text":
+			fmt.Println(report.ForTimelines(games))
+		case "csv":
+			b, err := report.TimelineCSV(games)
+			if err != nil {
+				log.Fatalf("could not generate the timeline: %v", err)
+			}
+			os.Stdout.Write(b)
+		case "svg":
+			// an svg document has only one chart
+			if len(games) != 1 {
+				log.Fatalf("the svg timeline needs the -game flag")
+			}
+			fmt.Print(report.TimelineSVG(games[0]))
+		}
+	default:
+		log.Fatalf("unknown report mode %q", *mode)
+	}
+}
+
+// findGame filters the game with the id, exiting when it does not exist
+func findGame(games []*parser.Game, id string) []*parser.Game {
+	for _, g := range games {
+		if g.ID == id {
+			return []*parser.Game{g}
+		}
+	}
+	log.Fatalf("game %s not found", id)
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
 	}
+	return false
 }
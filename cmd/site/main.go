@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/bgildson/enext-challenge/schema"
+	"github.com/bgildson/enext-challenge/site"
+)
+
+func main() {
+	gamesJSONPath := flag.String("games-json-path", "./games.json", "specify the json path of the parsed games.log")
+	out := flag.String("out", "./public", "specify the directory to write the html pages")
+	flag.Parse()
+
+	games, err := schema.ReadGames(*gamesJSONPath)
+	if err != nil {
+		log.Fatalf("could not load games from source: %v", err)
+	}
+
+	if err := site.Write(*out, games); err != nil {
+		log.Fatalf("could not generate the site: %v", err)
+	}
+	log.Printf("site with %d games written to %s", len(games), *out)
+}
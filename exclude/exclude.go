@@ -0,0 +1,184 @@
+package exclude
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// Config represents who should be left out of the rankings, the players by their exact names,
+// by regular expressions matching their names, every detected bot or the bots with some models
+type Config struct {
+	Names    []string `json:"names,omitempty"`
+	Patterns []string `json:"patterns,omitempty"`
+	Bots     bool     `json:"bots,omitempty"`
+	Models   []string `json:"models,omitempty"`
+}
+
+// Exclusions decides which players are left out of the rankings
+type Exclusions struct {
+	names    map[string]bool
+	patterns []*regexp.Regexp
+	bots     bool
+	models   []string
+}
+
+// New creates a new Exclusions instance
+func New(c Config) (*Exclusions, error) {
+	e := &Exclusions{
+		names:  map[string]bool{},
+		bots:   c.Bots,
+		models: c.Models,
+	}
+	for _, n := range c.Names {
+		e.names[n] = true
+	}
+	for _, p := range c.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", p, err)
+		}
+		e.patterns = append(e.patterns, re)
+	}
+	return e, nil
+}
+
+// Load reads the exclusions from a json file, like
+//
+//	{"names": ["Test"], "patterns": ["^\\[BOT\\]"], "bots": false, "models": ["sarge"]}
+func Load(path string) (*Exclusions, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the exclusions: %v", err)
+	}
+	var c Config
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("could not parse the exclusions: %v", err)
+	}
+	return New(c)
+}
+
+// Excluded reports if the player of the game is left out of the rankings
+func (e *Exclusions) Excluded(g *parser.Game, player string) bool {
+	if e.names[player] {
+		return true
+	}
+	for _, re := range e.patterns {
+		if re.MatchString(player) {
+			return true
+		}
+	}
+
+	model, bot := g.Bots[player]
+	if !bot {
+		return false
+	}
+	if e.bots {
+		return true
+	}
+	// the models can be informed without the skin, like sarge for sarge/krusade
+	for _, m := range e.models {
+		if model == m || strings.HasPrefix(model, m+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Games applies the exclusions to every game
+func (e *Exclusions) Games(gs []*parser.Game) []*parser.Game {
+	games := make([]*parser.Game, len(gs))
+	for i, g := range gs {
+		games[i] = e.Game(g)
+	}
+	return games
+}
+
+// Game returns a copy of the game without the results of the excluded players, nor the kills
+// of the sequence they are part of, the total kills and the team results still count their kills
+func (e *Exclusions) Game(g *parser.Game) *parser.Game {
+	if g == nil {
+		return nil
+	}
+
+	c := *g
+	c.Players = []string{}
+	c.Kills = map[string]int{}
+	for _, p := range g.Players {
+		if !e.Excluded(g, p) {
+			c.Players = append(c.Players, p)
+			c.Kills[p] = g.Kills[p]
+		}
+	}
+
+	c.CTF = nil
+	for p, s := range g.CTF {
+		if e.Excluded(g, p) {
+			continue
+		}
+		if c.CTF == nil {
+			c.CTF = map[string]*parser.CTFStats{}
+		}
+		c.CTF[p] = s
+	}
+
+	c.Weapons = nil
+	for p, s := range g.Weapons {
+		if e.Excluded(g, p) {
+			continue
+		}
+		if c.Weapons == nil {
+			c.Weapons = map[string]*parser.WeaponStats{}
+		}
+		c.Weapons[p] = s
+	}
+
+	c.TeamChanges = nil
+	for _, t := range g.TeamChanges {
+		if !e.Excluded(g, t.Player) {
+			c.TeamChanges = append(c.TeamChanges, t)
+		}
+	}
+
+	c.FriendlyFire = e.players(g, g.FriendlyFire)
+	c.PlayTime = e.players(g, g.PlayTime)
+
+	c.Bots = nil
+	for p, model := range g.Bots {
+		if e.Excluded(g, p) {
+			continue
+		}
+		if c.Bots == nil {
+			c.Bots = map[string]string{}
+		}
+		c.Bots[p] = model
+	}
+
+	c.KillSequence = nil
+	for _, k := range g.KillSequence {
+		if !e.Excluded(g, k.Killer) && !e.Excluded(g, k.Dead) {
+			c.KillSequence = append(c.KillSequence, k)
+		}
+	}
+
+	return &c
+}
+
+// players returns the values of the players not excluded
+func (e *Exclusions) players(g *parser.Game, m map[string]int) map[string]int {
+	var c map[string]int
+	for p, v := range m {
+		if e.Excluded(g, p) {
+			continue
+		}
+		if c == nil {
+			c = map[string]int{}
+		}
+		c[p] = v
+	}
+	return c
+}
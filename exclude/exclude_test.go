@@ -0,0 +1,161 @@
+package exclude
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+var game = &parser.Game{
+	ID:         "1",
+	TotalKills: 5,
+	Players:    []string{"Isgalamido", "Test", "[BOT]Mocinha", "Sarge", "Xaero"},
+	Kills:      map[string]int{"Isgalamido": 2, "Test": 1, "[BOT]Mocinha": 1, "Sarge": 1, "Xaero": 0},
+	CTF: map[string]*parser.CTFStats{
+		"Isgalamido": {Captures: 1},
+		"Test":       {Captures: 1},
+	},
+	Weapons: map[string]*parser.WeaponStats{
+		"Isgalamido": {Kills: map[string]int{"MOD_RAILGUN": 2}},
+		"Sarge":      {Kills: map[string]int{"MOD_ROCKET": 1}},
+	},
+	TeamChanges: []*parser.TeamChange{
+		{Player: "Isgalamido", Team: parser.TeamRed, Time: "0:01"},
+		{Player: "Test", Team: parser.TeamBlue, Time: "0:02"},
+		{Player: "Sarge", Team: parser.TeamBlue, Time: "0:03"},
+	},
+	FriendlyFire: map[string]int{"Test": 1, "[BOT]Mocinha": 1},
+	KillSequence: []*parser.Kill{
+		{Killer: "Isgalamido", Dead: "[BOT]Mocinha", Time: "0:10"},
+		{Killer: "Isgalamido", Dead: "Sarge", Time: "0:20"},
+		{Killer: "Sarge", Dead: "Isgalamido", Time: "0:30"},
+		{Killer: "<world>", Dead: "Test", Time: "0:40"},
+	},
+	Bots:     map[string]string{"Sarge": "sarge/krusade", "Xaero": "xaero"},
+	PlayTime: map[string]int{"Isgalamido": 60, "Test": 60, "Sarge": 30},
+}
+
+func TestExcluded(t *testing.T) {
+	tt := []struct {
+		description string
+		config      Config
+		out         []string
+	}{
+		{
+			description: "nothing",
+			out:         nil,
+		},
+		{
+			description: "names",
+			config:      Config{Names: []string{"Test", "Isga"}},
+			out:         []string{"Test"},
+		},
+		{
+			description: "patterns",
+			config:      Config{Patterns: []string{`^\[BOT\]`}},
+			out:         []string{"[BOT]Mocinha"},
+		},
+		{
+			description: "bots",
+			config:      Config{Bots: true},
+			out:         []string{"Sarge", "Xaero"},
+		},
+		{
+			description: "models",
+			config:      Config{Models: []string{"sarge", "uriel"}},
+			out:         []string{"Sarge"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			e, err := New(tc.config)
+			if err != nil {
+				t.Fatalf("an unexpected error occurred: %v", err)
+			}
+
+			var out []string
+			for _, p := range game.Players {
+				if e.Excluded(game, p) {
+					out = append(out, p)
+				}
+			}
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf("was expecting %v, but returns %v", tc.out, out)
+			}
+		})
+	}
+
+	if _, err := New(Config{Patterns: []string{"("}}); err == nil {
+		t.Errorf("was expecting an error for an invalid pattern")
+	}
+}
+
+func TestExclusionsGame(t *testing.T) {
+	e, err := New(Config{Names: []string{"Test"}, Bots: true})
+	if err != nil {
+		t.Fatalf("an unexpected error occurred: %v", err)
+	}
+
+	out := &parser.Game{
+		ID:         "1",
+		TotalKills: 5,
+		Players:    []string{"Isgalamido", "[BOT]Mocinha"},
+		Kills:      map[string]int{"Isgalamido": 2, "[BOT]Mocinha": 1},
+		CTF: map[string]*parser.CTFStats{
+			"Isgalamido": {Captures: 1},
+		},
+		Weapons: map[string]*parser.WeaponStats{
+			"Isgalamido": {Kills: map[string]int{"MOD_RAILGUN": 2}},
+		},
+		TeamChanges: []*parser.TeamChange{
+			{Player: "Isgalamido", Team: parser.TeamRed, Time: "0:01"},
+		},
+		FriendlyFire: map[string]int{"[BOT]Mocinha": 1},
+		KillSequence: []*parser.Kill{
+			{Killer: "Isgalamido", Dead: "[BOT]Mocinha", Time: "0:10"},
+		},
+		PlayTime: map[string]int{"Isgalamido": 60},
+	}
+
+	if g := e.Game(game); !reflect.DeepEqual(g, out) {
+		t.Errorf("was expecting %+v, but returns %+v", out, g)
+	}
+	if gs := e.Games([]*parser.Game{game}); !reflect.DeepEqual(gs, []*parser.Game{out}) {
+		t.Errorf("was expecting %+v, but returns %+v", out, gs[0])
+	}
+	if len(game.Players) != 5 {
+		t.Errorf("was expecting the original game unchanged, but returns %v", game.Players)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "exclusions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	valid := filepath.Join(dir, "valid.json")
+	ioutil.WriteFile(valid, []byte(`{"names": ["Test"], "bots": true}`), 0644)
+	invalid := filepath.Join(dir, "invalid.json")
+	ioutil.WriteFile(invalid, []byte(`{"names": "Test"}`), 0644)
+
+	e, err := Load(valid)
+	if err != nil {
+		t.Fatalf("an unexpected error occurred: %v", err)
+	}
+	if !e.Excluded(game, "Test") || !e.Excluded(game, "Xaero") || e.Excluded(game, "Isgalamido") {
+		t.Errorf("was expecting Test and Xaero excluded, but returns %+v", e)
+	}
+
+	for _, path := range []string{invalid, filepath.Join(dir, "missing.json")} {
+		if _, err := Load(path); err == nil {
+			t.Errorf("was expecting an error loading %s", path)
+		}
+	}
+}
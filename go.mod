@@ -4,5 +4,6 @@ go 1.14
 
 require (
 	github.com/go-chi/chi v4.1.2+incompatible
+	github.com/klauspost/compress v1.11.13
 	golang.org/x/net v0.0.0-20200707034311-ab3426394381 // indirect
 )
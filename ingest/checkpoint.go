@@ -0,0 +1,357 @@
+package ingest
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// IDStrategy indicates how the games are identified
+type IDStrategy string
+
+// Supported id strategies
+const (
+	SequentialIDs IDStrategy = "sequential"
+	ContentIDs    IDStrategy = "content"
+)
+
+// tailSize is how many bytes before the checkpoint offset are kept to detect a rewritten log
+const tailSize = 64
+
+// Checkpoint records how far a log was parsed, to resume from there in the next run.
+// The inode, offset, tail and file hash identify the last file read, while the hash covers every file.
+// Line is the number of the last line read in the file, used to locate the diagnostics
+type Checkpoint struct {
+	Inode      uint64             `json:"inode"`
+	Offset     int64              `json:"offset"`
+	Line       int                `json:"line"`
+	Tail       []byte             `json:"tail"`
+	FileHash   []byte             `json:"file_hash"`
+	Hash       []byte             `json:"hash"`
+	Games      int                `json:"games"`
+	Pending    []string           `json:"pending"`
+	PendingID  string             `json:"pending_id"`
+	ContentIDs *parser.ContentIDs `json:"content_ids,omitempty"`
+}
+
+// Checkpoints keeps the checkpoint of every log by its path
+type Checkpoints map[string]*Checkpoint
+
+// ReadCheckpoints reads the checkpoints file, a nonexistent file has no checkpoints
+func ReadCheckpoints(path string) (Checkpoints, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Checkpoints{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cps := Checkpoints{}
+	if err := json.Unmarshal(b, &cps); err != nil {
+		return nil, err
+	}
+	return cps, nil
+}
+
+// WriteCheckpoints writes the checkpoints file
+func WriteCheckpoints(path string, cps Checkpoints) error {
+	b, err := json.MarshalIndent(cps, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+// Options indicates how the logs are parsed
+type Options struct {
+	// Server labels the games, when informed
+	Server string
+	// IDs indicates how the games are identified
+	IDs IDStrategy
+	// Workers indicates how many games are processed concurrently
+	Workers int
+	// Resumable indicates the checkpoint will be used in the next run, so the content ids
+	// that still change while the last game is written are not handed out
+	Resumable bool
+}
+
+// fileStart indicates where the lines of a file begin, to locate the lines in the files
+type fileStart struct {
+	path  string
+	index int
+	line  int
+}
+
+// locate returns the file and the line number in the file of the line index
+func locate(starts []fileStart, index int) (string, int) {
+	s := starts[0]
+	for _, f := range starts {
+		if f.index > index {
+			break
+		}
+		s = f
+	}
+	return s.path, s.line + index - s.index
+}
+
+// Increment is the result of parsing the content appended to a log
+type Increment struct {
+	Games       []*parser.Game
+	Replaces    string
+	Diagnostics parser.Diagnostics
+	Checksum    string
+	Checkpoint  *Checkpoint
+}
+
+// ParseIncrement parses the log content written after the checkpoint, which is nil in the first run.
+// The rotated files are read before the current one as a continuous content, so a game split between
+// them is parsed as one. Only complete lines are parsed, a last line without the line break is considered
+// still being written. The last game of the log is kept pending, because it can still be written, and
+// is parsed again in the next run, so the first game found replaces the previously generated game with
+// the Replaces id. With content ids, when resumable, the pending game is generated only once its id is
+// settled. When the log was rotated since the checkpoint, the parsing continues from the rotated file,
+// even if it was compressed, or from the oldest file kept when the checkpointed one is gone, and
+// truncated logs are parsed from the beginning, keeping the games generated before. The lines not understood or out of sequence are diagnosed with their file and line number
+func ParseIncrement(l *Log, opts Options, cp *Checkpoint) (*Increment, error) {
+	inc := &Increment{}
+	next := &Checkpoint{}
+	h := sha256.New()
+	contentIDs := parser.NewContentIDs()
+	seq := 0
+	resume := false
+	var lines []string
+	var starts []fileStart
+
+	files := l.Files()
+
+	// the games generated before keep counting, even when the log was truncated
+	if cp != nil {
+		seq = cp.Games
+		if cp.ContentIDs != nil {
+			contentIDs = cp.ContentIDs
+		}
+
+		i, err := findCheckpointed(files, cp)
+		if err != nil {
+			return nil, err
+		}
+
+		// without the checkpointed file, it was rotated away and every file kept is newer,
+		// so the parsing continues from the oldest one
+		if i >= 0 {
+			files = files[i:]
+			resume = true
+
+			if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(cp.Hash); err != nil {
+				return nil, err
+			}
+
+			// the pending game is parsed again with the new lines
+			lines = append(lines, cp.Pending...)
+			if cp.PendingID != "" {
+				inc.Replaces = cp.PendingID
+				seq--
+				contentIDs.Release(cp.Pending)
+			}
+		}
+	}
+
+	// keeps a line that continues in the next file
+	partial := ""
+	for i, path := range files {
+		f, err := openLog(path)
+		if err != nil {
+			return nil, err
+		}
+
+		fh := sha256.New()
+		next.Inode = inode(f.info)
+		next.Offset = 0
+		next.Tail = nil
+
+		// a line continued from the previous file belongs to it
+		start := fileStart{path: path, index: len(lines), line: 1}
+		if partial != "" {
+			start.index++
+			start.line++
+		}
+
+		// the checkpointed file continues from the offset
+		if i == 0 && resume {
+			if err := skip(f, cp.Offset); err != nil {
+				f.Close()
+				return nil, err
+			}
+			if err := fh.(encoding.BinaryUnmarshaler).UnmarshalBinary(cp.FileHash); err != nil {
+				f.Close()
+				return nil, err
+			}
+			next.Offset = cp.Offset
+			next.Tail = cp.Tail
+			start.line = cp.Line + 1
+		}
+		starts = append(starts, start)
+
+		r := bufio.NewReader(f)
+		for {
+			s, err := r.ReadString('\n')
+			if err == io.EOF {
+				partial += s
+				break
+			}
+			if err != nil {
+				f.Close()
+				return nil, err
+			}
+
+			h.Write([]byte(partial + s))
+			fh.Write([]byte(s))
+			next.Offset += int64(len(s))
+			next.Tail = append(next.Tail, s...)
+			if len(next.Tail) > tailSize {
+				next.Tail = next.Tail[len(next.Tail)-tailSize:]
+			}
+
+			lines = append(lines, strings.TrimRight(partial+s, "\r\n"))
+			partial = ""
+		}
+		f.Close()
+
+		state, err := fh.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		next.FileHash = state
+	}
+
+	generator := func(n int, lines []string) string {
+		return strconv.Itoa(seq + n)
+	}
+	if opts.IDs == ContentIDs {
+		generator = contentIDs.Generate
+		next.ContentIDs = contentIDs
+	}
+
+	var diags parser.Diagnostics
+	inc.Games = parser.ProcessLinesParallel(lines, generator, opts.Workers, &diags)
+
+	// the pending lines were diagnosed in the previous run
+	pending := 0
+	if resume {
+		pending = len(cp.Pending)
+	}
+	for _, d := range diags {
+		if d.Line <= pending {
+			continue
+		}
+		d.Path, d.Line = locate(starts, d.Line-1)
+		inc.Diagnostics = append(inc.Diagnostics, d)
+	}
+	last := starts[len(starts)-1]
+	next.Line = last.line - 1
+	if n := len(lines) - last.index; n > 0 {
+		next.Line += n
+	}
+	if opts.Server != "" {
+		for _, g := range inc.Games {
+			g.SetServer(opts.Server)
+		}
+	}
+
+	// keeps the last game lines to parse it again in the next run, a content id that would still
+	// change with the next lines is not handed out, the game is generated when its id is settled
+	for i := len(lines) - 1; i >= 0; i-- {
+		if parser.NewLine(lines[i]).IsStartGame() {
+			next.Pending = lines[i:]
+			if opts.IDs == ContentIDs && opts.Resumable && !parser.ContentIDSettled(next.Pending) {
+				contentIDs.Release(next.Pending)
+				inc.Games = inc.Games[:len(inc.Games)-1]
+			} else {
+				next.PendingID = inc.Games[len(inc.Games)-1].ID
+			}
+			break
+		}
+	}
+	next.Games = seq + len(inc.Games)
+
+	state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	next.Hash = state
+
+	inc.Checksum = hex.EncodeToString(h.Sum(nil))
+	inc.Checkpoint = next
+
+	return inc, nil
+}
+
+// findCheckpointed returns the index of the file where the checkpoint was made, or -1 when not found,
+// looking from the current file to the older ones, which are probably the previous current files
+func findCheckpointed(files []string, cp *Checkpoint) (int, error) {
+	for i := len(files) - 1; i >= 0; i-- {
+		ok, err := isCheckpointed(files[i], cp)
+		if err != nil {
+			return -1, err
+		}
+		if ok {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// isCheckpointed verify if the file is the one where the checkpoint was made, with its content only appended since then
+func isCheckpointed(path string, cp *Checkpoint) (bool, error) {
+	f, err := openLog(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	if !f.compressed && f.info.Size() < cp.Offset {
+		return false, nil
+	}
+
+	// the same file is verified only by the content before the offset,
+	// a truncated file written again up to the offset has different content there
+	if !f.compressed && inode(f.info) == cp.Inode {
+		tail := make([]byte, len(cp.Tail))
+		if _, err := f.file.ReadAt(tail, cp.Offset-int64(len(tail))); err != nil {
+			return false, nil
+		}
+		return bytes.Equal(tail, cp.Tail), nil
+	}
+
+	// other files, like the rotated and compressed ones, should have the same content up to the offset
+	fh := sha256.New()
+	if _, err := io.CopyN(fh, f, cp.Offset); err != nil {
+		return false, nil
+	}
+	expected := sha256.New()
+	if err := expected.(encoding.BinaryUnmarshaler).UnmarshalBinary(cp.FileHash); err != nil {
+		return false, nil
+	}
+	return bytes.Equal(fh.Sum(nil), expected.Sum(nil)), nil
+}
+
+// skip advances the log to the offset
+func skip(f *logFile, offset int64) error {
+	if !f.compressed {
+		_, err := f.file.Seek(offset, io.SeekStart)
+		return err
+	}
+	_, err := io.CopyN(ioutil.Discard, f, offset)
+	return err
+}
@@ -0,0 +1,372 @@
+package ingest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+const (
+	gameOne = `  0:00 InitGame: \sv_hostname\Code Miner Server\mapname\q3dm17
+  0:10 Kill: 1022 2 22: <world> killed Isgalamido by MOD_TRIGGER_HURT
+  0:20 ShutdownGame:
+`
+	gameTwoBegin = `  1:00 InitGame: \sv_hostname\Code Miner Server\mapname\q3dm17
+  1:10 Kill: 2 3 7: Isgalamido killed Mocinha by MOD_ROCKET_SPLASH
+`
+	gameTwoEnd = `  1:20 Kill: 3 2 7: Mocinha killed Isgalamido by MOD_ROCKET_SPLASH
+  1:30 ShutdownGame:
+`
+	gameThree = `  2:00 InitGame: \sv_hostname\Code Miner Server\mapname\q3dm17
+  2:10 Kill: 1022 2 22: <world> killed Isgalamido by MOD_FALLING
+`
+)
+
+func gameIDs(games []*parser.Game) []string {
+	ids := []string{}
+	for _, g := range games {
+		ids = append(ids, g.ID)
+	}
+	return ids
+}
+
+func TestParseIncrement(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logs")
+	if err != nil {
+		t.Fatalf("could not create the logs directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "games.log")
+	write := func(content string, flag int) {
+		f, err := os.OpenFile(path, flag|os.O_WRONLY|os.O_CREATE, 0644)
+		if err != nil {
+			t.Fatalf("could not write the log: %v", err)
+		}
+		f.WriteString(content)
+		f.Close()
+	}
+
+	t.Run("resume appended logs", func(t *testing.T) {
+		write(gameOne+gameTwoBegin+"  1:15 Kill: 2 3", os.O_TRUNC)
+
+		inc, err := ParseIncrement(&Log{Path: path}, Options{IDs: SequentialIDs}, nil)
+		if err != nil {
+			t.Fatalf("an unexpected error occurred: %v", err)
+		}
+		if ids := gameIDs(inc.Games); !reflect.DeepEqual(ids, []string{"1", "2"}) || inc.Replaces != "" {
+			t.Errorf("was expecting games 1 and 2 without replaces, but returns %v replacing %q", ids, inc.Replaces)
+		}
+		if inc.Games[1].TotalKills != 1 {
+			t.Errorf("was expecting the incomplete line to be ignored, but returns %d kills", inc.Games[1].TotalKills)
+		}
+
+		// the incomplete line is finished and more games are written
+		write(" 7: Isgalamido killed Mocinha by MOD_ROCKET\n"+gameTwoEnd+gameThree, os.O_APPEND)
+
+		inc, err = ParseIncrement(&Log{Path: path}, Options{IDs: SequentialIDs}, inc.Checkpoint)
+		if err != nil {
+			t.Fatalf("an unexpected error occurred: %v", err)
+		}
+		if ids := gameIDs(inc.Games); !reflect.DeepEqual(ids, []string{"2", "3"}) || inc.Replaces != "2" {
+			t.Errorf("was expecting games 2 and 3 replacing 2, but returns %v replacing %q", ids, inc.Replaces)
+		}
+		if inc.Games[0].TotalKills != 3 {
+			t.Errorf("was expecting the pending game to have 3 kills, but returns %d", inc.Games[0].TotalKills)
+		}
+
+		// the checksum continues from the checkpoint
+		full, _ := ParseIncrement(&Log{Path: path}, Options{IDs: SequentialIDs}, nil)
+		if inc.Checksum != full.Checksum {
+			t.Errorf("was expecting %s checksum, but returns %s", full.Checksum, inc.Checksum)
+		}
+
+		// without new content the pending game is generated again
+		again, err := ParseIncrement(&Log{Path: path}, Options{IDs: SequentialIDs}, inc.Checkpoint)
+		if err != nil {
+			t.Fatalf("an unexpected error occurred: %v", err)
+		}
+		if ids := gameIDs(again.Games); !reflect.DeepEqual(ids, []string{"3"}) || again.Replaces != "3" {
+			t.Errorf("was expecting game 3 replacing 3, but returns %v replacing %q", ids, again.Replaces)
+		}
+	})
+
+	t.Run("restart truncated logs", func(t *testing.T) {
+		write(gameOne+gameTwoBegin, os.O_TRUNC)
+		inc, err := ParseIncrement(&Log{Path: path}, Options{Server: "server", IDs: SequentialIDs}, nil)
+		if err != nil {
+			t.Fatalf("an unexpected error occurred: %v", err)
+		}
+
+		// the log is rewritten with a bigger content, but different from the parsed one
+		write(gameThree+gameOne+gameTwoBegin, os.O_TRUNC)
+
+		inc, err = ParseIncrement(&Log{Path: path}, Options{Server: "server", IDs: SequentialIDs}, inc.Checkpoint)
+		if err != nil {
+			t.Fatalf("an unexpected error occurred: %v", err)
+		}
+		out := []string{"server-3", "server-4", "server-5"}
+		if ids := gameIDs(inc.Games); !reflect.DeepEqual(ids, out) || inc.Replaces != "" {
+			t.Errorf("was expecting %v without replaces, but returns %v replacing %q", out, ids, inc.Replaces)
+		}
+	})
+
+	t.Run("resume content ids", func(t *testing.T) {
+		write(gameOne+gameOne+gameTwoBegin, os.O_TRUNC)
+		full, _ := ParseIncrement(&Log{Path: path}, Options{IDs: ContentIDs}, nil)
+
+		write(gameOne+gameOne, os.O_TRUNC)
+		inc, err := ParseIncrement(&Log{Path: path}, Options{IDs: ContentIDs}, nil)
+		if err != nil {
+			t.Fatalf("an unexpected error occurred: %v", err)
+		}
+
+		write(gameTwoBegin, os.O_APPEND)
+		inc, err = ParseIncrement(&Log{Path: path}, Options{IDs: ContentIDs}, inc.Checkpoint)
+		if err != nil {
+			t.Fatalf("an unexpected error occurred: %v", err)
+		}
+
+		// the repeated game pending in the checkpoint keeps its id
+		out := gameIDs(full.Games)[1:]
+		if ids := gameIDs(inc.Games); !reflect.DeepEqual(ids, out) || inc.Replaces != out[0] {
+			t.Errorf("was expecting %v replacing %s, but returns %v replacing %q", out, out[0], ids, inc.Replaces)
+		}
+	})
+
+	t.Run("defer the unsettled content ids", func(t *testing.T) {
+		write(gameOne+gameTwoBegin+gameTwoEnd, os.O_TRUNC)
+		full, _ := ParseIncrement(&Log{Path: path}, Options{IDs: ContentIDs}, nil)
+
+		write(gameOne+gameTwoBegin, os.O_TRUNC)
+		inc, err := ParseIncrement(&Log{Path: path}, Options{IDs: ContentIDs, Resumable: true}, nil)
+		if err != nil {
+			t.Fatalf("an unexpected error occurred: %v", err)
+		}
+
+		// the short game still being written is not generated yet
+		out := gameIDs(full.Games)[:1]
+		if ids := gameIDs(inc.Games); !reflect.DeepEqual(ids, out) || inc.Checkpoint.PendingID != "" {
+			t.Errorf("was expecting %v without pending id, but returns %v pending %q", out, ids, inc.Checkpoint.PendingID)
+		}
+
+		write(gameTwoEnd, os.O_APPEND)
+		inc, err = ParseIncrement(&Log{Path: path}, Options{IDs: ContentIDs, Resumable: true}, inc.Checkpoint)
+		if err != nil {
+			t.Fatalf("an unexpected error occurred: %v", err)
+		}
+
+		// the game shutdown has the same id of the full parse, without replacing any game
+		out = gameIDs(full.Games)[1:]
+		if ids := gameIDs(inc.Games); !reflect.DeepEqual(ids, out) || inc.Replaces != "" {
+			t.Errorf("was expecting %v without replaces, but returns %v replacing %q", out, ids, inc.Replaces)
+		}
+	})
+
+	t.Run("many workers", func(t *testing.T) {
+		write(gameOne+gameOne+gameTwoBegin+gameTwoEnd+gameThree, os.O_TRUNC)
+		full, _ := ParseIncrement(&Log{Path: path}, Options{IDs: ContentIDs}, nil)
+
+		inc, err := ParseIncrement(&Log{Path: path}, Options{IDs: ContentIDs, Workers: 4}, nil)
+		if err != nil {
+			t.Fatalf("an unexpected error occurred: %v", err)
+		}
+		if !reflect.DeepEqual(inc, full) {
+			t.Errorf("was expecting %v, but returns %v", full, inc)
+		}
+	})
+}
+
+func TestParseIncrementRotated(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logs")
+	if err != nil {
+		t.Fatalf("could not create the logs directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	current := filepath.Join(dir, "games.log")
+	rotated := filepath.Join(dir, "games.log.1.gz")
+	write := func(path, content string, compress bool) {
+		var b bytes.Buffer
+		if compress {
+			w := gzip.NewWriter(&b)
+			w.Write([]byte(content))
+			w.Close()
+		} else {
+			b.WriteString(content)
+		}
+		if err := ioutil.WriteFile(path, b.Bytes(), 0644); err != nil {
+			t.Fatalf("could not write the log: %v", err)
+		}
+	}
+
+	t.Run("read the rotated files as a continuous log", func(t *testing.T) {
+		write(rotated, gameOne+gameTwoBegin+"  1:15 Kill: 2 3", true)
+		write(current, " 7: Isgalamido killed Mocinha by MOD_ROCKET\n"+gameTwoEnd, false)
+
+		l := &Log{Path: current, Rotated: []string{rotated}}
+		inc, err := ParseIncrement(l, Options{IDs: SequentialIDs}, nil)
+		if err != nil {
+			t.Fatalf("an unexpected error occurred: %v", err)
+		}
+		if ids := gameIDs(inc.Games); !reflect.DeepEqual(ids, []string{"1", "2"}) {
+			t.Errorf("was expecting games 1 and 2, but returns %v", ids)
+		}
+		if inc.Games[1].TotalKills != 3 {
+			t.Errorf("was expecting the split game to have 3 kills, but returns %d", inc.Games[1].TotalKills)
+		}
+	})
+
+	t.Run("resume from the rotated and compressed file", func(t *testing.T) {
+		os.Remove(rotated)
+		write(current, gameOne+gameTwoBegin, false)
+
+		l := &Log{Path: current}
+		inc, err := ParseIncrement(l, Options{IDs: SequentialIDs}, nil)
+		if err != nil {
+			t.Fatalf("an unexpected error occurred: %v", err)
+		}
+
+		// the log keeps being written and is rotated and compressed, a new log is started
+		write(rotated, gameOne+gameTwoBegin+gameTwoEnd, true)
+		write(current, gameThree, false)
+		os.Chtimes(current, time.Now(), time.Now())
+
+		l = &Log{Path: current, Rotated: []string{rotated}}
+		inc, err = ParseIncrement(l, Options{IDs: SequentialIDs}, inc.Checkpoint)
+		if err != nil {
+			t.Fatalf("an unexpected error occurred: %v", err)
+		}
+		if ids := gameIDs(inc.Games); !reflect.DeepEqual(ids, []string{"2", "3"}) || inc.Replaces != "2" {
+			t.Errorf("was expecting games 2 and 3 replacing 2, but returns %v replacing %q", ids, inc.Replaces)
+		}
+		if inc.Games[0].TotalKills != 2 {
+			t.Errorf("was expecting the pending game to have 2 kills, but returns %d", inc.Games[0].TotalKills)
+		}
+
+		// the checksum covers every file
+		full, _ := ParseIncrement(l, Options{IDs: SequentialIDs}, nil)
+		if inc.Checksum != full.Checksum {
+			t.Errorf("was expecting %s checksum, but returns %s", full.Checksum, inc.Checksum)
+		}
+	})
+
+	t.Run("resume from the oldest file when the checkpointed one was rotated away", func(t *testing.T) {
+		os.Remove(rotated)
+		write(current, gameOne+gameTwoBegin, false)
+
+		l := &Log{Path: current}
+		inc, err := ParseIncrement(l, Options{IDs: SequentialIDs}, nil)
+		if err != nil {
+			t.Fatalf("an unexpected error occurred: %v", err)
+		}
+
+		// the log is rotated twice and the file with the checkpoint is not kept
+		write(rotated, gameThree, true)
+		write(current, gameOne, false)
+		os.Chtimes(current, time.Now(), time.Now())
+
+		l = &Log{Path: current, Rotated: []string{rotated}}
+		inc, err = ParseIncrement(l, Options{IDs: SequentialIDs}, inc.Checkpoint)
+		if err != nil {
+			t.Fatalf("an unexpected error occurred: %v", err)
+		}
+		if ids := gameIDs(inc.Games); !reflect.DeepEqual(ids, []string{"3", "4"}) || inc.Replaces != "" {
+			t.Errorf("was expecting games 3 and 4 without replaces, but returns %v replacing %q", ids, inc.Replaces)
+		}
+	})
+}
+
+func TestParseIncrementDiagnostics(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logs")
+	if err != nil {
+		t.Fatalf("could not create the logs directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	current := filepath.Join(dir, "games.log")
+	rotated := filepath.Join(dir, "games.log.1")
+	write := func(path, content string) {
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("could not write the log: %v", err)
+		}
+	}
+	locations := func(ds parser.Diagnostics) []string {
+		r := []string{}
+		for _, d := range ds {
+			r = append(r, d.Location())
+		}
+		return r
+	}
+
+	write(rotated, gameOne+"  0:30 Teleport: 2\n  0:31 Tele")
+	write(current, "port: 3\n"+gameTwoBegin+"  1:15 Teleport: 4\n")
+
+	l := &Log{Path: current, Rotated: []string{rotated}}
+	inc, err := ParseIncrement(l, Options{IDs: SequentialIDs}, nil)
+	if err != nil {
+		t.Fatalf("an unexpected error occurred: %v", err)
+	}
+	out := []string{rotated + ":4", rotated + ":5", current + ":4"}
+	if r := locations(inc.Diagnostics); !reflect.DeepEqual(r, out) {
+		t.Errorf("was expecting %v, but returns %v", out, r)
+	}
+
+	// the pending game is not diagnosed again
+	write(current, "port: 3\n"+gameTwoBegin+"  1:15 Teleport: 4\n"+gameThree)
+	inc, err = ParseIncrement(l, Options{IDs: SequentialIDs}, inc.Checkpoint)
+	if err != nil {
+		t.Fatalf("an unexpected error occurred: %v", err)
+	}
+	out = []string{current + ":5"}
+	if r := locations(inc.Diagnostics); !reflect.DeepEqual(r, out) {
+		t.Errorf("was expecting %v, but returns %v", out, r)
+	}
+	if inc.Checkpoint.Line != 6 {
+		t.Errorf("was expecting the checkpoint at the line 6, but returns %d", inc.Checkpoint.Line)
+	}
+}
+
+func TestCheckpoints(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoints")
+	if err != nil {
+		t.Fatalf("could not create the checkpoints directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "checkpoints.json")
+
+	cps, err := ReadCheckpoints(path)
+	if err != nil || len(cps) != 0 {
+		t.Errorf("was expecting no checkpoints, but returns %v %v", cps, err)
+	}
+
+	cps["games.log"] = &Checkpoint{
+		Inode:      1,
+		Offset:     10,
+		Tail:       []byte("tail"),
+		Hash:       []byte("hash"),
+		Games:      2,
+		Pending:    []string{"  0:00 InitGame:"},
+		PendingID:  "2",
+		ContentIDs: &parser.ContentIDs{Seen: map[string]int{"abc": 1}},
+	}
+	if err := WriteCheckpoints(path, cps); err != nil {
+		t.Errorf("an unexpected error occurred: %v", err)
+	}
+
+	r, err := ReadCheckpoints(path)
+	if err != nil {
+		t.Errorf("an unexpected error occurred: %v", err)
+	}
+	if !reflect.DeepEqual(r, cps) {
+		t.Errorf("was expecting %v, but returns %v", cps, r)
+	}
+}
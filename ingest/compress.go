@@ -0,0 +1,96 @@
+package ingest
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// magic numbers of the supported compression formats
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// logFile is a log opened for reading, decompressed when needed
+type logFile struct {
+	io.Reader
+	file       *os.File
+	info       os.FileInfo
+	compressed bool
+	close      func()
+}
+
+// Close closes the decompressor and the file
+func (f *logFile) Close() error {
+	if f.close != nil {
+		f.close()
+	}
+	return f.file.Close()
+}
+
+// openLog opens a log file, detecting by the magic numbers if the content
+// is compressed with gzip, bzip2 or zstd to decompress it while reading
+func openLog(path string) (*logFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	br := bufio.NewReader(f)
+	magic, _ := br.Peek(len(zstdMagic))
+
+	lf := &logFile{Reader: br, file: f, info: info, compressed: true}
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		r, err := gzip.NewReader(br)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		lf.Reader = r
+		lf.close = func() { r.Close() }
+
+	case bytes.HasPrefix(magic, bzip2Magic):
+		lf.Reader = bzip2.NewReader(br)
+
+	case bytes.HasPrefix(magic, zstdMagic):
+		r, err := zstd.NewReader(br)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		lf.Reader = r
+		lf.close = r.Close
+
+	default:
+		// plain logs are read directly from the file, allowing to seek
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			f.Close()
+			return nil, err
+		}
+		lf.Reader = f
+		lf.compressed = false
+	}
+
+	return lf, nil
+}
+
+// Open opens a log file to be read line by line, decompressing
+// the logs compressed with gzip, bzip2 or zstd
+func Open(path string) (io.ReadCloser, error) {
+	return openLog(path)
+}
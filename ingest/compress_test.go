@@ -0,0 +1,106 @@
+package ingest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestOpenLog(t *testing.T) {
+	basePath, err := os.Getwd()
+	if err != nil {
+		t.Errorf("could not determine where the app is running: %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "logs")
+	if err != nil {
+		t.Fatalf("could not create the logs directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the fixtures/game.log.bz2 has the gameOne content
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	gw.Write([]byte(gameOne))
+	gw.Close()
+
+	var zst bytes.Buffer
+	zw, _ := zstd.NewWriter(&zst)
+	zw.Write([]byte(gameOne))
+	zw.Close()
+
+	files := map[string][]byte{
+		"plain.log":      []byte(gameOne),
+		"gzip.log.gz":    gz.Bytes(),
+		"zstd.log":       zst.Bytes(),
+		"empty.log":      {},
+		"truncated.gzip": gz.Bytes()[:4],
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatalf("could not write the log: %v", err)
+		}
+	}
+
+	tt := []struct {
+		description string
+		in          string
+		out         string
+		compressed  bool
+		err         bool
+	}{
+		{"plain", filepath.Join(dir, "plain.log"), gameOne, false, false},
+		{"gzip", filepath.Join(dir, "gzip.log.gz"), gameOne, true, false},
+		{"bzip2", path.Join(basePath, "..", "fixtures", "game.log.bz2"), gameOne, true, false},
+		{"zstd without extension", filepath.Join(dir, "zstd.log"), gameOne, true, false},
+		{"empty", filepath.Join(dir, "empty.log"), "", false, false},
+		{"malformed gzip", filepath.Join(dir, "truncated.gzip"), "", true, true},
+		{"nonexistent", filepath.Join(dir, "nonexistent.log"), "", false, true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			f, err := openLog(tc.in)
+			if err != nil {
+				if !tc.err {
+					t.Errorf("an unexpected error occurred: %v", err)
+				}
+				return
+			}
+			defer f.Close()
+
+			b, err := ioutil.ReadAll(f)
+			if (err != nil) != tc.err {
+				t.Errorf("was expecting error %v, but returns %v", tc.err, err)
+			}
+			if !tc.err && string(b) != tc.out {
+				t.Errorf("was expecting\n%v\nbut returns\n%v", tc.out, string(b))
+			}
+			if f.compressed != tc.compressed {
+				t.Errorf("was expecting compressed %v, but returns %v", tc.compressed, f.compressed)
+			}
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	f, err := Open(filepath.Join("..", "fixtures", "game.log.bz2"))
+	if err != nil {
+		t.Fatalf("an unexpected error occurred: %v", err)
+	}
+	defer f.Close()
+
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatalf("an unexpected error occurred: %v", err)
+	}
+	if string(b) != gameOne {
+		t.Errorf("was expecting\n%v\nbut returns\n%v", gameOne, string(b))
+	}
+}
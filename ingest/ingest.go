@@ -0,0 +1,180 @@
+package ingest
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Reusable errors
+var (
+	ErrNoLogs            = errors.New("could not find any log")
+	ErrLabelWithManyLogs = errors.New("a label can only be used with a single log")
+)
+
+// Log is a log to be parsed, labeled with the server that generated it.
+// Rotated keeps the older files of the log, in chronological order, that are read before Path
+type Log struct {
+	Label string
+	// Labeled indicates the label was informed, instead of taken from the file name
+	Labeled bool
+	Path    string
+	Rotated []string
+}
+
+// Files returns every file of the log in chronological order
+func (l *Log) Files() []string {
+	return append(append([]string{}, l.Rotated...), l.Path)
+}
+
+// labelName matches the labels, which prefix the game ids, like server1 or br-sp.1
+var labelName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// logName matches the names of log files, rotated like games.log.1 and compressed like games.log.2.gz
+var logName = regexp.MustCompile(`^(.*\.log)(?:\.(\d+))?(?:\.(?:gz|bz2|zst))?$`)
+
+// Expand resolves the informed logs, which can be files, directories or globs and optionally
+// prefixed by a label, like "server1=./server1.log", into the list of logs to parse. The text before
+// the "=" is a label only when it is a valid label and the whole text is not an existing file.
+// Rotated files found in directories or globs, like games.log.2.gz and games.log.1,
+// are grouped with the current log, games.log, to be read as a single log.
+// Logs are labeled with their file names without the extensions
+func Expand(specs []string) ([]*Log, error) {
+	var logs []*Log
+	labels := map[string]string{}
+
+	for _, spec := range specs {
+		label, pattern := splitLabel(spec)
+
+		paths, err := resolve(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		series := group(paths)
+		if label != "" && len(series) > 1 {
+			return nil, ErrLabelWithManyLogs
+		}
+
+		for _, l := range series {
+			if label != "" {
+				l.Label = label
+				l.Labeled = true
+			}
+
+			// the labels prefix the game ids, so they should be unique
+			if other, ok := labels[l.Label]; ok {
+				return nil, fmt.Errorf("the logs %s and %s have the same label %q", other, l.Path, l.Label)
+			}
+			labels[l.Label] = l.Path
+
+			logs = append(logs, l)
+		}
+	}
+
+	if len(logs) == 0 {
+		return nil, ErrNoLogs
+	}
+
+	return logs, nil
+}
+
+// splitLabel separates the label of a log, like "server1=./server1.log", the paths with "=" are kept
+// whole when they exist or when the text before the "=" is not a label
+func splitLabel(spec string) (label, pattern string) {
+	i := strings.Index(spec, "=")
+	if i < 0 || !labelName.MatchString(spec[:i]) {
+		return "", spec
+	}
+	if _, err := os.Stat(spec); err == nil {
+		return "", spec
+	}
+	return spec[:i], spec[i+1:]
+}
+
+// group groups the rotated files with their current log, keeping the order of the paths
+func group(paths []string) []*Log {
+	type rotated struct {
+		path  string
+		index int
+	}
+
+	var bases []string
+	files := map[string][]rotated{}
+	for _, p := range paths {
+		base, index := p, 0
+		if m := logName.FindStringSubmatch(filepath.Base(p)); m != nil {
+			base = filepath.Join(filepath.Dir(p), m[1])
+			index, _ = strconv.Atoi(m[2])
+		}
+		if _, ok := files[base]; !ok {
+			bases = append(bases, base)
+		}
+		files[base] = append(files[base], rotated{p, index})
+	}
+
+	var logs []*Log
+	for _, base := range bases {
+		fs := files[base]
+
+		// bigger indexes are older
+		sort.SliceStable(fs, func(i, j int) bool {
+			return fs[i].index > fs[j].index
+		})
+
+		l := &Log{
+			Label: strings.TrimSuffix(filepath.Base(base), filepath.Ext(base)),
+			Path:  fs[len(fs)-1].path,
+		}
+		for _, f := range fs[:len(fs)-1] {
+			l.Rotated = append(l.Rotated, f.path)
+		}
+		logs = append(logs, l)
+	}
+
+	return logs
+}
+
+// resolve returns the files matched by a file, directory or glob, ordered by name
+func resolve(pattern string) ([]string, error) {
+	info, err := os.Stat(pattern)
+	if err == nil && !info.IsDir() {
+		return []string{pattern}, nil
+	}
+
+	var paths []string
+	if err == nil {
+		files, err := ioutil.ReadDir(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if !f.IsDir() && logName.MatchString(f.Name()) {
+				paths = append(paths, filepath.Join(pattern, f.Name()))
+			}
+		}
+	} else {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && !info.IsDir() {
+				paths = append(paths, m)
+			}
+		}
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%v: %s", ErrNoLogs, pattern)
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
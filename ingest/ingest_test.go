@@ -0,0 +1,138 @@
+package ingest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logs")
+	if err != nil {
+		t.Fatalf("could not create the logs directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"b.log", "a.log", "notes.txt", "other/c.log", "rotated/games.log", "rotated/games.log.1", "rotated/games.log.10.gz", "rotated/games.log.2.bz2", "rotated/other.log.1.zst", "equal/a=b.log", "equal/x=y.log"} {
+		p := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(p), 0755)
+		if err := ioutil.WriteFile(p, []byte{}, 0644); err != nil {
+			t.Fatalf("could not create the log: %v", err)
+		}
+	}
+
+	tt := []struct {
+		description string
+		in          []string
+		out         []*Log
+		err         bool
+	}{
+		{
+			description: "a single file",
+			in:          []string{filepath.Join(dir, "a.log")},
+			out:         []*Log{{Label: "a", Path: filepath.Join(dir, "a.log")}},
+		},
+		{
+			description: "a labeled file",
+			in:          []string{"server=" + filepath.Join(dir, "a.log")},
+			out:         []*Log{{Label: "server", Labeled: true, Path: filepath.Join(dir, "a.log")}},
+		},
+		{
+			description: "a directory",
+			in:          []string{dir},
+			out: []*Log{
+				{Label: "a", Path: filepath.Join(dir, "a.log")},
+				{Label: "b", Path: filepath.Join(dir, "b.log")},
+			},
+		},
+		{
+			description: "a glob",
+			in:          []string{filepath.Join(dir, "o*", "*.log")},
+			out:         []*Log{{Label: "c", Path: filepath.Join(dir, "other", "c.log")}},
+		},
+		{
+			description: "many sources",
+			in:          []string{filepath.Join(dir, "b.log"), filepath.Join(dir, "other")},
+			out: []*Log{
+				{Label: "b", Path: filepath.Join(dir, "b.log")},
+				{Label: "c", Path: filepath.Join(dir, "other", "c.log")},
+			},
+		},
+		{
+			description: "rotated logs",
+			in:          []string{filepath.Join(dir, "rotated")},
+			out: []*Log{
+				{
+					Label: "games",
+					Path:  filepath.Join(dir, "rotated", "games.log"),
+					Rotated: []string{
+						filepath.Join(dir, "rotated", "games.log.10.gz"),
+						filepath.Join(dir, "rotated", "games.log.2.bz2"),
+						filepath.Join(dir, "rotated", "games.log.1"),
+					},
+				},
+				{Label: "other", Path: filepath.Join(dir, "rotated", "other.log.1.zst")},
+			},
+		},
+		{
+			description: "a labeled rotated log",
+			in:          []string{"server=" + filepath.Join(dir, "rotated", "games.log*")},
+			out: []*Log{
+				{
+					Label:   "server",
+					Labeled: true,
+					Path:    filepath.Join(dir, "rotated", "games.log"),
+					Rotated: []string{
+						filepath.Join(dir, "rotated", "games.log.10.gz"),
+						filepath.Join(dir, "rotated", "games.log.2.bz2"),
+						filepath.Join(dir, "rotated", "games.log.1"),
+					},
+				},
+			},
+		},
+		{
+			description: "an existing file with =",
+			in:          []string{filepath.Join(dir, "equal", "a=b.log")},
+			out:         []*Log{{Label: "a=b", Path: filepath.Join(dir, "equal", "a=b.log")}},
+		},
+		{
+			description: "a labeled file with =",
+			in:          []string{"server=" + filepath.Join(dir, "equal", "x=y.log")},
+			out:         []*Log{{Label: "server", Labeled: true, Path: filepath.Join(dir, "equal", "x=y.log")}},
+		},
+		{
+			description: "a glob with =",
+			in:          []string{filepath.Join(dir, "equal", "*=b.log")},
+			out:         []*Log{{Label: "a=b", Path: filepath.Join(dir, "equal", "a=b.log")}},
+		},
+		{
+			description: "a label for many logs",
+			in:          []string{"server=" + dir},
+			err:         true,
+		},
+		{
+			description: "repeated labels",
+			in:          []string{"a=" + filepath.Join(dir, "b.log"), filepath.Join(dir, "a.log")},
+			err:         true,
+		},
+		{
+			description: "nothing found",
+			in:          []string{filepath.Join(dir, "*.gz")},
+			err:         true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			logs, err := Expand(tc.in)
+			if (err != nil) != tc.err {
+				t.Errorf("was expecting error %v, but returns %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(logs, tc.out) {
+				t.Errorf("was expecting %v, but returns %v", tc.out, logs)
+			}
+		})
+	}
+}
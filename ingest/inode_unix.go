@@ -0,0 +1,17 @@
+//go:build !windows
+// +build !windows
+
+package ingest
+
+import (
+	"os"
+	"syscall"
+)
+
+// inode identifies the file in the file system, used to detect rotated logs
+func inode(info os.FileInfo) uint64 {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Ino)
+	}
+	return 0
+}
@@ -0,0 +1,252 @@
+package loggen
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// Config indicates how the synthetic games are generated
+type Config struct {
+	Seed    int64
+	Games   int
+	Players int
+	Kills   int
+	// Renames is how many times the players change their names in every game
+	Renames int
+	// Weapons are the means of death used by the players, all the Weapons when empty
+	Weapons []string
+	// Abnormal is the probability of a game to end without a ShutdownGame, like when the server crashes
+	Abnormal float64
+}
+
+// DefaultConfig generates a log similar to the challenge games.log
+var DefaultConfig = Config{
+	Seed:    1,
+	Games:   21,
+	Players: 4,
+	Kills:   50,
+}
+
+// meansIDs are the means of death with the ids written in the kill lines
+var meansIDs = map[string]int{
+	"MOD_SHOTGUN":       1,
+	"MOD_MACHINEGUN":    3,
+	"MOD_ROCKET":        6,
+	"MOD_ROCKET_SPLASH": 7,
+	"MOD_RAILGUN":       10,
+	"MOD_BFG":           12,
+	"MOD_BFG_SPLASH":    13,
+	"MOD_CRUSH":         17,
+	"MOD_TELEFRAG":      18,
+	"MOD_FALLING":       19,
+	"MOD_TRIGGER_HURT":  22,
+}
+
+// Weapons are the means used by the players and Hazards the ones used by the world
+var (
+	Weapons = []string{"MOD_SHOTGUN", "MOD_MACHINEGUN", "MOD_ROCKET", "MOD_ROCKET_SPLASH", "MOD_RAILGUN", "MOD_BFG", "MOD_BFG_SPLASH", "MOD_TELEFRAG"}
+	Hazards = []string{"MOD_TRIGGER_HURT", "MOD_FALLING", "MOD_CRUSH"}
+)
+
+// names are the player names, the players after them are numbered
+var names = []string{"Isgalamido", "Dono da Bola", "Mocinha", "Zeh", "Oootsimo", "Assasinu Credi", "Chessus", "Mal"}
+
+// worldID is the client id of the kills caused by the map
+const worldID = 1022
+
+// userinfo is the format of the line announcing the client info, with its name
+const userinfo = `ClientUserinfoChanged: %d n\%s\t\0\model\sarge\hmodel\sarge\g_redteam\\g_blueteam\\c1\4\c2\5\hc\100\w\0\l\0\tt\0\tl\0`
+
+func playerName(i int) string {
+	if i < len(names) {
+		return names[i]
+	}
+	return fmt.Sprintf("Player %d", i+1)
+}
+
+// Generate creates the events of synthetic games, in the order they happen
+func Generate(c Config) []*parser.Event {
+	r := rand.New(rand.NewSource(c.Seed))
+
+	var es []*parser.Event
+	weapons := c.Weapons
+	if len(weapons) == 0 {
+		weapons = Weapons
+	}
+
+	for g := 1; g <= c.Games; g++ {
+		id := strconv.Itoa(g)
+
+		// the game clock advances a few seconds on every event
+		seconds := 0
+		event := func(t parser.EventType) *parser.Event {
+			e := &parser.Event{
+				Type:   t,
+				GameID: id,
+				Time:   fmt.Sprintf("%d:%02d", seconds/60, seconds%60),
+			}
+			es = append(es, e)
+			seconds += r.Intn(10)
+			return e
+		}
+
+		event(parser.EventGameStart)
+
+		var players []string
+		for p := 0; p < c.Players; p++ {
+			players = append(players, playerName(p))
+			event(parser.EventJoin).Player = players[p]
+		}
+
+		// the renames happen between the kills
+		renames := map[int]int{}
+		for i := 0; i < c.Renames && len(players) > 0; i++ {
+			renames[r.Intn(c.Kills+1)]++
+		}
+		named := len(players)
+
+		for k := 0; k <= c.Kills && len(players) > 0; k++ {
+			for i := 0; i < renames[k]; i++ {
+				p := r.Intn(len(players))
+				e := event(parser.EventRename)
+				e.Previous = players[p]
+				e.Player = playerName(named)
+				players[p] = e.Player
+				named++
+			}
+			if k == c.Kills {
+				break
+			}
+
+			e := event(parser.EventKill)
+			dead := r.Intn(len(players))
+			e.Dead = players[dead]
+			if r.Intn(5) == 0 {
+				e.Killer = "<world>"
+				e.Means = Hazards[r.Intn(len(Hazards))]
+				continue
+			}
+
+			// the players rarely kill themselves
+			killer := r.Intn(len(players))
+			if killer == dead && len(players) > 1 && r.Intn(4) > 0 {
+				killer = (dead + 1 + r.Intn(len(players)-1)) % len(players)
+			}
+			e.Killer = players[killer]
+			e.Means = weapons[r.Intn(len(weapons))]
+		}
+
+		if r.Float64() >= c.Abnormal {
+			event(parser.EventGameEnd)
+		}
+	}
+
+	return es
+}
+
+// Lines formats the events as the server writes them in the log,
+// the players are identified by client ids assigned in the order they join every game
+func Lines(events []*parser.Event) []string {
+	var lines []string
+	line := func(e *parser.Event, format string, a ...interface{}) {
+		lines = append(lines, fmt.Sprintf("%6s ", e.Time)+fmt.Sprintf(format, a...))
+	}
+
+	ids := map[string]int{}
+	next := 2
+	for _, e := range events {
+		switch e.Type {
+		case parser.EventGameStart:
+			ids = map[string]int{}
+			next = 2
+			line(e, "------------------------------------------------------------")
+			line(e, `InitGame: \sv_hostname\Code Miner Server\g_gametype\0\sv_maxclients\16\fraglimit\20\timelimit\15\mapname\q3dm17\gamename\baseq3`)
+
+		case parser.EventJoin:
+			id := next
+			next++
+			ids[e.Player] = id
+			line(e, "ClientConnect: %d", id)
+			line(e, userinfo, id, e.Player)
+			line(e, "ClientBegin: %d", id)
+
+		case parser.EventRename:
+			id := ids[e.Previous]
+			delete(ids, e.Previous)
+			ids[e.Player] = id
+			line(e, userinfo, id, e.Player)
+
+		case parser.EventKill:
+			killer := worldID
+			if e.Killer != "<world>" {
+				killer = ids[e.Killer]
+			}
+			line(e, "Kill: %d %d %d: %s killed %s by %s", killer, ids[e.Dead], meansIDs[e.Means], e.Killer, e.Dead, e.Means)
+
+		case parser.EventGameEnd:
+			line(e, "ShutdownGame:")
+			line(e, "------------------------------------------------------------")
+		}
+	}
+
+	return lines
+}
+
+// Games returns the games the events should be parsed to, identified by their sequence
+func Games(events []*parser.Event) []*parser.Game {
+	var gs []*parser.Game
+
+	// the players play from their join until the game end, or the last event of the games without end,
+	// the time before a rename is played with the previous name
+	joined := map[string]int{}
+	last := 0
+	end := func() {
+		for p, start := range joined {
+			gs[len(gs)-1].AddPlayTime(p, last-start)
+		}
+		joined = map[string]int{}
+	}
+
+	for _, e := range events {
+		if e.Type == parser.EventGameStart && len(gs) > 0 {
+			end()
+		}
+		d, _ := parser.ParseClock(e.Time)
+		last = int(d / time.Second)
+
+		switch e.Type {
+		case parser.EventGameStart:
+			g := parser.NewGameEmpty()
+			g.ID = strconv.Itoa(len(gs) + 1)
+			gs = append(gs, g)
+
+		case parser.EventJoin:
+			joined[e.Player] = last
+
+		case parser.EventRename:
+			gs[len(gs)-1].AddPlayTime(e.Previous, last-joined[e.Previous])
+			joined[e.Player] = last
+			delete(joined, e.Previous)
+
+		case parser.EventKill:
+			gs[len(gs)-1].AddKill(&parser.Kill{
+				Killer: e.Killer,
+				Dead:   e.Dead,
+				Means:  e.Means,
+				Time:   e.Time,
+			})
+
+		case parser.EventGameEnd:
+			end()
+		}
+	}
+	if len(gs) > 0 {
+		end()
+	}
+
+	return gs
+}
@@ -0,0 +1,89 @@
+package loggen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+func TestGenerate(t *testing.T) {
+	c := Config{Seed: 7, Games: 3, Players: 10, Kills: 20, Renames: 2}
+
+	es := Generate(c)
+	if n := c.Games * (1 + c.Players + c.Renames + c.Kills + 1); len(es) != n {
+		t.Errorf("was expecting %d events, but returns %d", n, len(es))
+	}
+	if !reflect.DeepEqual(es, Generate(c)) {
+		t.Errorf("was expecting the same events for the same seed")
+	}
+	c.Seed = 8
+	if reflect.DeepEqual(es, Generate(c)) {
+		t.Errorf("was expecting other events for other seed")
+	}
+
+	t.Run("weapons", func(t *testing.T) {
+		for _, e := range Generate(Config{Seed: 1, Games: 2, Players: 4, Kills: 50, Weapons: []string{"MOD_RAILGUN"}}) {
+			if e.Type == parser.EventKill && e.Killer != "<world>" && e.Means != "MOD_RAILGUN" {
+				t.Errorf("was expecting only MOD_RAILGUN kills, but returns %s", e.Means)
+			}
+		}
+	})
+
+	t.Run("abnormal terminations", func(t *testing.T) {
+		ends := 0
+		for _, e := range Generate(Config{Seed: 1, Games: 10, Players: 2, Kills: 5, Abnormal: 1}) {
+			if e.Type == parser.EventGameEnd {
+				ends++
+			}
+		}
+		if ends != 0 {
+			t.Errorf("was expecting every game to end abnormally, but returns %d ends", ends)
+		}
+	})
+}
+
+func TestLines(t *testing.T) {
+	tt := []struct {
+		description string
+		in          Config
+	}{
+		{description: "default", in: DefaultConfig},
+		{description: "many players", in: Config{Seed: 2, Games: 5, Players: 12, Kills: 100}},
+		{description: "without players", in: Config{Seed: 3, Games: 2}},
+		{description: "renames", in: Config{Seed: 4, Games: 5, Players: 4, Kills: 30, Renames: 6}},
+		{description: "abnormal terminations", in: Config{Seed: 5, Games: 10, Players: 4, Kills: 30, Abnormal: 0.5}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			es := Generate(tc.in)
+			lines := Lines(es)
+
+			if r, out := parser.ProcessLines(lines), Games(es); !reflect.DeepEqual(r, out) {
+				t.Errorf("was expecting %v, but returns %v", out, r)
+			}
+
+			// the lines are parsed back to the same events, a game without ShutdownGame
+			// is ended by the parser when the next one starts
+			p := parser.NewEventParser()
+			var r []*parser.Event
+			for _, l := range lines {
+				for _, e := range p.Parse(l) {
+					if e.Type != parser.EventGameEnd || tc.in.Abnormal == 0 {
+						r = append(r, e)
+					}
+				}
+			}
+			var out []*parser.Event
+			for _, e := range es {
+				if e.Type != parser.EventGameEnd || tc.in.Abnormal == 0 {
+					out = append(out, e)
+				}
+			}
+			if !reflect.DeepEqual(r, out) {
+				t.Errorf("was expecting the generated events to be parsed from the lines")
+			}
+		})
+	}
+}
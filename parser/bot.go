@@ -0,0 +1,26 @@
+package parser
+
+import "strings"
+
+// userinfoBot extracts the model of a bot from a ClientUserinfoChanged payload,
+// the server announces the skill only for the bots
+func userinfoBot(payload string) (model string, ok bool) {
+	i := strings.IndexByte(payload, ' ')
+	if i < 0 {
+		return "", false
+	}
+	info := payload[i+1:]
+	if _, ok := infoValue(info, "skill"); !ok {
+		return "", false
+	}
+	model, _ = infoValue(info, "model")
+	return model, true
+}
+
+// AddBot marks the player as a bot, with its model
+func (g *Game) AddBot(player, model string) {
+	if g.Bots == nil {
+		g.Bots = map[string]string{}
+	}
+	g.Bots[player] = model
+}
@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUserinfoBot(t *testing.T) {
+	tt := []struct {
+		description string
+		in          string
+		model       string
+		ok          bool
+	}{
+		{
+			description: "bot",
+			in:          `3 n\Sarge\t\0\model\sarge\hmodel\sarge\g_redteam\\g_blueteam\\c1\4\c2\5\hc\70\w\0\l\0\skill\ 3.00\tt\0\tl\0`,
+			model:       "sarge",
+			ok:          true,
+		},
+		{
+			description: "player",
+			in:          `2 n\Isgalamido\t\0\model\uriel/zael\hmodel\uriel/zael\g_redteam\\g_blueteam\\c1\5\c2\5\hc\100\w\0\l\0\tt\0\tl\0`,
+		},
+		{
+			description: "without info",
+			in:          `2`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			model, ok := userinfoBot(tc.in)
+			if model != tc.model || ok != tc.ok {
+				t.Errorf("was expecting %q and %v, but returns %q and %v", tc.model, tc.ok, model, ok)
+			}
+		})
+	}
+}
+
+func TestProcessLinesBots(t *testing.T) {
+	lines := []string{
+		`  0:00 InitGame: \sv_hostname\Code Miner Server\g_gametype\0\mapname\q3dm17`,
+		`  0:01 ClientUserinfoChanged: 2 n\Isgalamido\t\0\model\uriel/zael\hmodel\uriel/zael\l\0\tt\0\tl\0`,
+		`  0:02 ClientUserinfoChanged: 3 n\Sarge\t\0\model\sarge\hmodel\sarge\l\0\skill\ 3.00\tt\0\tl\0`,
+		`  0:10 Kill: 3 2 10: Sarge killed Isgalamido by MOD_RAILGUN`,
+		`  0:20 ShutdownGame:`,
+	}
+
+	games := ProcessLines(lines)
+	if len(games) != 1 {
+		t.Fatalf("was expecting 1 game, but returns %d", len(games))
+	}
+
+	out := map[string]string{"Sarge": "sarge"}
+	if !reflect.DeepEqual(games[0].Bots, out) {
+		t.Errorf("was expecting %v, but returns %v", out, games[0].Bots)
+	}
+}
@@ -0,0 +1,28 @@
+package parser
+
+import (
+	"strings"
+	"time"
+)
+
+// ParseClock converts the game clock, like 0:00 or 123:45, to the time since the game started,
+// reporting if the text is a game clock
+func ParseClock(clock string) (time.Duration, bool) {
+	i := strings.IndexByte(clock, ':')
+	if i < 1 || len(clock)-i != 3 {
+		return 0, false
+	}
+	m, s := 0, 0
+	for j, c := range clock {
+		switch {
+		case j == i:
+		case c < '0' || c > '9':
+			return 0, false
+		case j < i:
+			m = m*10 + int(c-'0')
+		default:
+			s = s*10 + int(c-'0')
+		}
+	}
+	return time.Duration(m)*time.Minute + time.Duration(s)*time.Second, true
+}
@@ -0,0 +1,32 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseClock(t *testing.T) {
+	tt := []struct {
+		in  string
+		out time.Duration
+		ok  bool
+	}{
+		{"0:00", 0, true},
+		{"1:47", time.Minute + 47*time.Second, true},
+		{"20:54", 20*time.Minute + 54*time.Second, true},
+		{"123:45", 123*time.Minute + 45*time.Second, true},
+		{"20", 0, false},
+		{"1:7", 0, false},
+		{":54", 0, false},
+		{"a:54", 0, false},
+		{"-----", 0, false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.in, func(t *testing.T) {
+			if r, ok := ParseClock(tc.in); r != tc.out || ok != tc.ok {
+				t.Errorf("was expecting %v %v, but returns %v %v", tc.out, tc.ok, r, ok)
+			}
+		})
+	}
+}
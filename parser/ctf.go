@@ -0,0 +1,176 @@
+package parser
+
+import (
+	"strings"
+)
+
+// CTFStats represents the capture the flag results of a player
+type CTFStats struct {
+	Pickups      int `json:"pickups"`
+	Captures     int `json:"captures"`
+	Returns      int `json:"returns"`
+	CarrierKills int `json:"carrier_kills"`
+}
+
+// flagItems are the items of the flags, named by the team owning them
+var flagItems = map[string]string{
+	"team_CTF_redflag":  TeamRed,
+	"team_CTF_blueflag": TeamBlue,
+}
+
+// ctfActions are the actions logged in the CTF lines, like "CTF: 2 2 0: Isgalamido got the BLUE flag!"
+var ctfActions = map[string]EventType{
+	"0": EventFlagPickup,
+	"1": EventFlagCapture,
+	"2": EventFlagReturn,
+	"3": EventCarrierKill,
+}
+
+// ctfTracker follows the flags of a capture the flag game converting the flag lines to events.
+// Some servers log the flag actions in CTF lines, right after the item pickup of the flag, others
+// only log the pickups, so the actions are inferred from the pickups until a CTF line is found
+type ctfTracker struct {
+	teams    map[string]string
+	carriers map[string]string
+	logged   bool
+	pending  *Event
+}
+
+func newCTFTracker() *ctfTracker {
+	return &ctfTracker{
+		teams:    map[string]string{},
+		carriers: map[string]string{},
+	}
+}
+
+// setTeam records the team of a client, used to know if a flag pickup is from the own flag
+func (t *ctfTracker) setTeam(id, team string) {
+	t.teams[id] = team
+}
+
+// next resolves the event inferred from the previous line before handling a new line,
+// it is dropped when the line is the CTF line logging the same action
+func (t *ctfTracker) next(keyword string) []*Event {
+	e := t.pending
+	t.pending = nil
+	if e == nil || keyword == "CTF" {
+		return nil
+	}
+	t.apply(e)
+	return []*Event{e}
+}
+
+// apply updates the flag carriers with the event
+func (t *ctfTracker) apply(e *Event) {
+	switch e.Type {
+	case EventFlagPickup:
+		t.carriers[e.Team] = e.Player
+	case EventFlagCapture, EventCarrierKill:
+		delete(t.carriers, e.Team)
+	}
+}
+
+// parse handles a line of the game, returning the flag events it produced
+func (t *ctfTracker) parse(clock, keyword, payload string, names map[string]string) []*Event {
+	switch keyword {
+	case "CTF":
+		t.logged = true
+
+		i := strings.Index(payload, ":")
+		if i < 0 {
+			return nil
+		}
+		fields := strings.Fields(payload[:i])
+		if len(fields) != 3 {
+			return nil
+		}
+		action, ok := ctfActions[fields[2]]
+		flag, known := teams[fields[1]]
+		if !ok || !known {
+			return nil
+		}
+
+		e := &Event{Type: action, Time: clock, Team: flag}
+		if action == EventCarrierKill {
+			e.Killer = names[fields[0]]
+			e.Dead = t.carriers[flag]
+		} else {
+			e.Player = names[fields[0]]
+		}
+		t.apply(e)
+		return []*Event{e}
+
+	case "Item":
+		fields := strings.Fields(payload)
+		if t.logged || len(fields) != 2 {
+			return nil
+		}
+		flag, ok := flagItems[fields[1]]
+		team := t.teams[fields[0]]
+		if !ok || team == "" {
+			return nil
+		}
+
+		// the own flag is picked up to be returned or, carrying the enemy flag, to capture it
+		e := &Event{Type: EventFlagPickup, Time: clock, Player: names[fields[0]], Team: flag}
+		if team == flag {
+			e.Type = EventFlagReturn
+			for f, carrier := range t.carriers {
+				if carrier == e.Player {
+					e.Type = EventFlagCapture
+					e.Team = f
+				}
+			}
+		}
+		t.pending = e
+
+	case "Kill":
+		k, ok := parseKill(payload, names)
+		if !ok || t.logged {
+			return nil
+		}
+
+		// the flag carried by the dead is dropped
+		for f, carrier := range t.carriers {
+			if carrier != k.Dead {
+				continue
+			}
+			delete(t.carriers, f)
+			if k.Killer != "<world>" && k.Killer != k.Dead {
+				return []*Event{{Type: EventCarrierKill, Time: clock, Killer: k.Killer, Dead: k.Dead, Team: f}}
+			}
+		}
+	}
+
+	return nil
+}
+
+// AddCTFEvent handles a flag event, attributing it to the player
+func (g *Game) AddCTFEvent(e *Event) {
+	player := e.Player
+	if e.Type == EventCarrierKill {
+		player = e.Killer
+	}
+	if player == "" {
+		return
+	}
+
+	if g.CTF == nil {
+		g.CTF = map[string]*CTFStats{}
+	}
+	if _, ok := g.CTF[player]; !ok {
+		g.CTF[player] = &CTFStats{}
+	}
+
+	s := g.CTF[player]
+	switch e.Type {
+	case EventFlagPickup:
+		s.Pickups++
+	case EventFlagCapture:
+		s.Captures++
+	case EventFlagReturn:
+		s.Returns++
+	case EventCarrierKill:
+		s.CarrierKills++
+	}
+}
@@ -0,0 +1,140 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGameAddCTFEvent(t *testing.T) {
+	tt := []struct {
+		description string
+		in          []*Event
+		out         map[string]*CTFStats
+	}{
+		{
+			description: "without events",
+			in:          nil,
+			out:         nil,
+		},
+		{
+			description: "the flag actions of the players",
+			in: []*Event{
+				{Type: EventFlagPickup, Player: "Isgalamido", Team: TeamBlue},
+				{Type: EventFlagCapture, Player: "Isgalamido", Team: TeamBlue},
+				{Type: EventFlagReturn, Player: "Mocinha", Team: TeamBlue},
+				{Type: EventCarrierKill, Killer: "Mocinha", Dead: "Isgalamido", Team: TeamBlue},
+			},
+			out: map[string]*CTFStats{
+				"Isgalamido": {Pickups: 1, Captures: 1},
+				"Mocinha":    {Returns: 1, CarrierKills: 1},
+			},
+		},
+		{
+			description: "an action of an unknown player",
+			in: []*Event{
+				{Type: EventCarrierKill, Killer: "", Dead: "Isgalamido", Team: TeamBlue},
+			},
+			out: nil,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			g := NewGameEmpty()
+			for _, e := range tc.in {
+				g.AddCTFEvent(e)
+			}
+			if !reflect.DeepEqual(g.CTF, tc.out) {
+				t.Errorf("was expecting %v, but returns %v", tc.out, g.CTF)
+			}
+		})
+	}
+}
+
+func TestProcessLinesCTF(t *testing.T) {
+	start := []string{
+		`  0:00 InitGame: \sv_hostname\Code Miner Server\g_gametype\4\capturelimit\8\mapname\q3ctf1`,
+		`  0:01 ClientUserinfoChanged: 2 n\Isgalamido\t\1\model\xian/default`,
+		`  0:02 ClientUserinfoChanged: 3 n\Dono da Bola\t\2\model\sarge`,
+		`  0:03 ClientUserinfoChanged: 4 n\Mocinha\t\2\model\sarge`,
+	}
+
+	tt := []struct {
+		description string
+		in          []string
+		out         map[string]*CTFStats
+	}{
+		{
+			description: "actions inferred from the flag pickups",
+			in: []string{
+				`  0:10 Item: 2 team_CTF_blueflag`,
+				`  0:20 Item: 2 team_CTF_redflag`,
+				`  0:30 Item: 3 team_CTF_redflag`,
+				`  0:40 Kill: 4 3 10: Mocinha killed Dono da Bola by MOD_RAILGUN`,
+				`  0:50 Item: 2 team_CTF_redflag`,
+				`  0:55 Item: 2 team_CTF_blueflag`,
+				`  0:58 Kill: 1022 2 22: <world> killed Isgalamido by MOD_TRIGGER_HURT`,
+				`  1:00 Item: 4 team_CTF_blueflag`,
+				`  1:10 ShutdownGame:`,
+			},
+			out: map[string]*CTFStats{
+				"Isgalamido":   {Pickups: 2, Captures: 1, Returns: 1},
+				"Dono da Bola": {Pickups: 1},
+				"Mocinha":      {Returns: 1, CarrierKills: 1},
+			},
+		},
+		{
+			description: "actions logged in the ctf lines",
+			in: []string{
+				`  0:10 Item: 2 team_CTF_blueflag`,
+				`  0:10 CTF: 2 2 0: Isgalamido got the BLUE flag!`,
+				`  0:20 Item: 2 team_CTF_redflag`,
+				`  0:20 CTF: 2 2 1: Isgalamido captured the BLUE flag!`,
+				`  0:30 Item: 3 team_CTF_redflag`,
+				`  0:30 CTF: 3 1 0: Dono da Bola got the RED flag!`,
+				`  0:40 Kill: 4 3 10: Mocinha killed Dono da Bola by MOD_RAILGUN`,
+				`  0:40 CTF: 2 1 3: Isgalamido fragged RED's flag carrier!`,
+				`  0:50 CTF: 2 1 2: The RED flag has returned!`,
+				`  1:10 ShutdownGame:`,
+			},
+			out: map[string]*CTFStats{
+				"Isgalamido":   {Pickups: 1, Captures: 1, Returns: 1, CarrierKills: 1},
+				"Dono da Bola": {Pickups: 1},
+			},
+		},
+		{
+			description: "a flag picked up in the last line",
+			in: []string{
+				`  0:10 Item: 3 team_CTF_redflag`,
+			},
+			out: map[string]*CTFStats{
+				"Dono da Bola": {Pickups: 1},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			gs := ProcessLines(append(append([]string{}, start...), tc.in...))
+			if len(gs) != 1 {
+				t.Fatalf("was expecting 1 game, but returns %d", len(gs))
+			}
+			if !reflect.DeepEqual(gs[0].CTF, tc.out) {
+				t.Errorf("was expecting %v, but returns %v", tc.out, gs[0].CTF)
+			}
+		})
+	}
+}
+
+func TestProcessLinesCTFOnlyInCTFGames(t *testing.T) {
+	lines := []string{
+		`  0:00 InitGame: \sv_hostname\Code Miner Server\g_gametype\3\mapname\q3ctf1`,
+		`  0:01 ClientUserinfoChanged: 2 n\Isgalamido\t\1\model\xian/default`,
+		`  0:10 Item: 2 team_CTF_blueflag`,
+		`  0:20 ShutdownGame:`,
+	}
+
+	if gs := ProcessLines(lines); len(gs) != 1 || gs[0].CTF != nil {
+		t.Errorf("was expecting no ctf stats, but returns %v", gs[0].CTF)
+	}
+}
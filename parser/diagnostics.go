@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Reasons why a line is diagnosed
+const (
+	ReasonUnrecognisedLine    = "unrecognised line"
+	ReasonUnknownEvent        = "unknown event"
+	ReasonMalformedKill       = "malformed kill"
+	ReasonKillOutsideGame     = "kill outside of a game"
+	ReasonShutdownOutsideGame = "shutdown outside of a game"
+	ReasonGameWithoutShutdown = "game started before the previous one shutdown"
+)
+
+// knownEvents are the event keywords written by the server, even the ones not used by the parser
+var knownEvents = map[string]bool{
+	"InitGame":              true,
+	"ShutdownGame":          true,
+	"Exit":                  true,
+	"Warmup":                true,
+	"Kill":                  true,
+	"Item":                  true,
+	"ClientConnect":         true,
+	"ClientUserinfoChanged": true,
+	"ClientBegin":           true,
+	"ClientDisconnect":      true,
+	"score":                 true,
+	"say":                   true,
+	"sayteam":               true,
+	"tell":                  true,
+	"red":                   true,
+	"CTF":                   true,
+}
+
+// Diagnostic describes a log line the parser could not understand or that is out of sequence
+type Diagnostic struct {
+	Path   string `json:"path,omitempty"`
+	Line   int    `json:"line"`
+	Text   string `json:"text"`
+	Reason string `json:"reason"`
+}
+
+// Location returns where the line is, like ./games.log:97
+func (d *Diagnostic) Location() string {
+	if d.Path == "" {
+		return fmt.Sprintf("line %d", d.Line)
+	}
+	return fmt.Sprintf("%s:%d", d.Path, d.Line)
+}
+
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %q", d.Location(), d.Reason, d.Text)
+}
+
+// Diagnostics collects the diagnostics found while parsing the logs
+type Diagnostics []*Diagnostic
+
+// Add records a diagnostic for the line, the line number starts from 1,
+// it does nothing on a nil collector, so the diagnostics are optional
+func (ds *Diagnostics) Add(line int, text, reason string) {
+	if ds == nil {
+		return
+	}
+	*ds = append(*ds, &Diagnostic{
+		Line:   line,
+		Text:   text,
+		Reason: reason,
+	})
+}
+
+// Summary describes how many lines were diagnosed by every reason, with the first line of each one
+func (ds Diagnostics) Summary() string {
+	var reasons []string
+	count := map[string]int{}
+	first := map[string]*Diagnostic{}
+	for _, d := range ds {
+		if count[d.Reason] == 0 {
+			reasons = append(reasons, d.Reason)
+			first[d.Reason] = d
+		}
+		count[d.Reason]++
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d lines could not be parsed", len(ds))
+	for _, r := range reasons {
+		fmt.Fprintf(&b, "\n  %s: %d, first at %s", r, count[r], first[r].Location())
+	}
+	return b.String()
+}
+
+// lineReason verify if the line follows the log format with a known event,
+// returning why it does not, or an empty string for a valid line
+func lineReason(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+
+	clock, keyword, _, ok := splitLine(text)
+	if !ok {
+		// the separators between the games, like "0:00 -----"
+		if i := strings.IndexByte(text, ' '); i > 0 && strings.Trim(text[i+1:], "-") == "" {
+			if _, clock := ParseClock(text[:i]); clock {
+				return ""
+			}
+		}
+		return ReasonUnrecognisedLine
+	}
+	if _, ok := ParseClock(clock); !ok {
+		return ReasonUnrecognisedLine
+	}
+	if !knownEvents[keyword] {
+		return ReasonUnknownEvent
+	}
+	return ""
+}
@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestLineReason(t *testing.T) {
+	tt := []struct {
+		in  string
+		out string
+	}{
+		{
+			in:  "",
+			out: "",
+		},
+		{
+			in:  "  0:00 ------------------------------------------------------------",
+			out: "",
+		},
+		{
+			in:  "  1:47 ClientConnect: 2",
+			out: "",
+		},
+		{
+			in:  "22:06 Kill: 2 3 7: Isgalamido killed Mocinha by MOD_ROCKET_SPLASH",
+			out: "",
+		},
+		{
+			in:  "1234:06 score: 20  ping: 4  client: 4 Zeh",
+			out: "",
+		},
+		{
+			in:  " 26  0:00 ------------------------------------------------------------",
+			out: ReasonUnrecognisedLine,
+		},
+		{
+			in:  "ClientConnect: 2",
+			out: ReasonUnrecognisedLine,
+		},
+		{
+			in:  "1:4 ClientConnect: 2",
+			out: ReasonUnrecognisedLine,
+		},
+		{
+			in:  "  1:47 Teleport: 2",
+			out: ReasonUnknownEvent,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.in, func(t *testing.T) {
+			if r := lineReason(tc.in); r != tc.out {
+				t.Errorf("was expecting %q, but returns %q", tc.out, r)
+			}
+		})
+	}
+}
+
+func TestDiagnosticsAdd(t *testing.T) {
+	var nilDiags *Diagnostics
+	nilDiags.Add(1, "  1:47 Teleport: 2", ReasonUnknownEvent)
+
+	var ds Diagnostics
+	ds.Add(1, "  1:47 Teleport: 2", ReasonUnknownEvent)
+	if len(ds) != 1 {
+		t.Errorf("was expecting 1 diagnostic, but returns %d", len(ds))
+	}
+}
+
+func TestDiagnosticsSummary(t *testing.T) {
+	ds := Diagnostics{
+		{Path: "./games.log", Line: 3, Text: "  1:47 Teleport: 2", Reason: ReasonUnknownEvent},
+		{Line: 5, Text: "invalid", Reason: ReasonUnrecognisedLine},
+		{Path: "./games.log", Line: 8, Text: "  1:50 Teleport: 3", Reason: ReasonUnknownEvent},
+	}
+
+	out := "3 lines could not be parsed\n" +
+		"  unknown event: 2, first at ./games.log:3\n" +
+		"  unrecognised line: 1, first at line 5"
+	if r := ds.Summary(); r != out {
+		t.Errorf("was expecting %q, but returns %q", out, r)
+	}
+
+	if r := ds[0].String(); r != `./games.log:3: unknown event: "  1:47 Teleport: 2"` {
+		t.Errorf("was expecting the diagnostic description, but returns %q", r)
+	}
+}
@@ -0,0 +1,186 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// EventType identifies which kind of event happened in a game
+type EventType string
+
+// Supported event types
+const (
+	EventGameStart EventType = "game_start"
+	EventGameEnd   EventType = "game_end"
+	EventJoin      EventType = "join"
+	EventRename    EventType = "rename"
+	EventKill      EventType = "kill"
+
+	EventFlagPickup  EventType = "flag_pickup"
+	EventFlagCapture EventType = "flag_capture"
+	EventFlagReturn  EventType = "flag_return"
+	EventCarrierKill EventType = "carrier_kill"
+)
+
+// Event represents something that happened in a game
+type Event struct {
+	Type     EventType `json:"type"`
+	GameID   string    `json:"game_id"`
+	Time     string    `json:"time"`
+	Player   string    `json:"player,omitempty"`
+	Previous string    `json:"previous,omitempty"`
+	Killer   string    `json:"killer,omitempty"`
+	Dead     string    `json:"dead,omitempty"`
+	Means    string    `json:"means,omitempty"`
+	// Team is the team owning the flag of the capture the flag events
+	Team string `json:"team,omitempty"`
+}
+
+// splitLine separates a log line in the game clock, the event keyword and the payload
+// when the line does not follow the "clock keyword: payload" format, ok is false
+func splitLine(text string) (clock, keyword, payload string, ok bool) {
+	text = strings.TrimLeft(text, " ")
+
+	i := strings.IndexByte(text, ' ')
+	if i < 0 {
+		return "", "", "", false
+	}
+	clock, rest := text[:i], text[i+1:]
+
+	j := strings.IndexByte(rest, ':')
+	if j < 0 {
+		return "", "", "", false
+	}
+	keyword, payload = rest[:j], strings.TrimLeft(rest[j+1:], " ")
+
+	return clock, keyword, payload, true
+}
+
+// userinfoName extracts the player name from a ClientUserinfoChanged payload
+func userinfoName(payload string) (id, name string, ok bool) {
+	i := strings.IndexByte(payload, ' ')
+	if i < 0 {
+		return "", "", false
+	}
+	id, info := payload[:i], payload[i+1:]
+
+	if !strings.HasPrefix(info, `n\`) {
+		return "", "", false
+	}
+	name = info[2:]
+	if j := strings.IndexByte(name, '\\'); j >= 0 {
+		name = name[:j]
+	}
+
+	return id, name, true
+}
+
+// EventParser converts log lines into typed events, keeping track of the
+// current game and of the names announced by every client
+type EventParser struct {
+	gameID  int
+	running bool
+	clients map[string]string
+	flags   *ctfTracker
+}
+
+// NewEventParser creates a new EventParser instance
+func NewEventParser() *EventParser {
+	return &EventParser{
+		clients: map[string]string{},
+	}
+}
+
+// Parse handles one log line and returns the events it produced, usually none or one
+func (p *EventParser) Parse(text string) []*Event {
+	clock, keyword, payload, ok := splitLine(text)
+	if !ok {
+		return nil
+	}
+
+	var es []*Event
+	if p.flags != nil {
+		es = append(es, p.flagEvents(p.flags.next(keyword))...)
+	}
+
+	switch keyword {
+	case "InitGame":
+		// games without a ShutdownGame are closed when the next one starts
+		if p.running {
+			es = append(es, p.event(EventGameEnd, clock))
+		}
+		p.gameID++
+		p.running = true
+		p.clients = map[string]string{}
+		p.flags = nil
+		if gameType(payload) == GameTypeCTF {
+			p.flags = newCTFTracker()
+		}
+		es = append(es, p.event(EventGameStart, clock))
+
+	case "ShutdownGame":
+		if p.running {
+			es = append(es, p.event(EventGameEnd, clock))
+			p.running = false
+		}
+		p.flags = nil
+
+	case "ClientConnect":
+		// the ids are reused by the next clients, a new connection is not a rename
+		delete(p.clients, strings.TrimSpace(payload))
+
+	case "ClientUserinfoChanged":
+		if id, name, ok := userinfoName(payload); ok {
+			// the clients announce their info many times, only a different name is a rename
+			if previous, ok := p.clients[id]; ok && previous != name && p.running {
+				e := p.event(EventRename, clock)
+				e.Player = name
+				e.Previous = previous
+				es = append(es, e)
+			}
+			p.clients[id] = name
+			if team, ok := userinfoTeam(payload); ok && p.flags != nil {
+				p.flags.setTeam(id, team)
+			}
+		}
+
+	case "ClientBegin":
+		if name, ok := p.clients[strings.TrimSpace(payload)]; ok && p.running {
+			e := p.event(EventJoin, clock)
+			e.Player = name
+			es = append(es, e)
+		}
+
+	case "Kill":
+		k, ok := parseKill(payload, p.clients)
+		if ok && p.running {
+			e := p.event(EventKill, clock)
+			e.Killer = k.Killer
+			e.Dead = k.Dead
+			e.Means = k.Means
+			es = append(es, e)
+		}
+	}
+
+	if p.flags != nil && p.running {
+		es = append(es, p.flagEvents(p.flags.parse(clock, keyword, payload, p.clients))...)
+	}
+
+	return es
+}
+
+// flagEvents assigns the current game to the events of the flags
+func (p *EventParser) flagEvents(es []*Event) []*Event {
+	for _, e := range es {
+		e.GameID = strconv.Itoa(p.gameID)
+	}
+	return es
+}
+
+func (p *EventParser) event(t EventType, clock string) *Event {
+	return &Event{
+		Type:   t,
+		GameID: strconv.Itoa(p.gameID),
+		Time:   clock,
+	}
+}
@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEventParserParse(t *testing.T) {
+	tt := []struct {
+		description string
+		in          []string
+		out         []*Event
+	}{
+		{
+			description: "a normal game",
+			in: []string{
+				`  0:00 ------------------------------------------------------------`,
+				`  0:00 InitGame: \sv_floodProtect\1\sv_hostname\Code Miner Server\g_gametype\0\mapname\q3dm17`,
+				` 20:38 ClientConnect: 2`,
+				` 20:38 ClientUserinfoChanged: 2 n\Isgalamido\t\0\model\uriel/zael\hmodel\uriel/zael`,
+				` 20:38 ClientBegin: 2`,
+				` 20:40 Item: 2 weapon_rocketlauncher`,
+				` 20:54 Kill: 1022 2 22: <world> killed Isgalamido by MOD_TRIGGER_HURT`,
+				` 21:51 ClientConnect: 3`,
+				` 21:51 ClientUserinfoChanged: 3 n\Dono da Bola\t\0\model\sarge/krusade\hmodel\sarge/krusade`,
+				` 21:53 ClientBegin: 3`,
+				` 22:06 Kill: 2 3 7: Isgalamido killed Dono da Bola by MOD_ROCKET_SPLASH`,
+				` 22:27 ShutdownGame:`,
+			},
+			out: []*Event{
+				{Type: EventGameStart, GameID: "1", Time: "0:00"},
+				{Type: EventJoin, GameID: "1", Time: "20:38", Player: "Isgalamido"},
+				{Type: EventKill, GameID: "1", Time: "20:54", Killer: "<world>", Dead: "Isgalamido", Means: "MOD_TRIGGER_HURT"},
+				{Type: EventJoin, GameID: "1", Time: "21:53", Player: "Dono da Bola"},
+				{Type: EventKill, GameID: "1", Time: "22:06", Killer: "Isgalamido", Dead: "Dono da Bola", Means: "MOD_ROCKET_SPLASH"},
+				{Type: EventGameEnd, GameID: "1", Time: "22:27"},
+			},
+		},
+		{
+			description: "a kill with the means of death parsed like the game",
+			in: []string{
+				`  0:00 InitGame: \sv_hostname\Code Miner Server\g_gametype\0\mapname\q3dm17`,
+				`  0:05 ClientUserinfoChanged: 2 n\Stand by Me\t\0\model\uriel/zael`,
+				`  0:10 Kill: 2 2 7: Stand by Me killed Stand by Me by MOD_ROCKET_SPLASH  `,
+				`  0:20 ShutdownGame:`,
+			},
+			out: []*Event{
+				{Type: EventGameStart, GameID: "1", Time: "0:00"},
+				{Type: EventKill, GameID: "1", Time: "0:10", Killer: "Stand by Me", Dead: "Stand by Me", Means: "MOD_ROCKET_SPLASH"},
+				{Type: EventGameEnd, GameID: "1", Time: "0:20"},
+			},
+		},
+		{
+			description: "a game without shutdown",
+			in: []string{
+				`  0:00 InitGame: \sv_hostname\Code Miner Server\g_gametype\0\mapname\q3dm17`,
+				`  0:10 Kill: 1022 2 22: <world> killed Isgalamido by MOD_TRIGGER_HURT`,
+				`  1:47 InitGame: \sv_hostname\Code Miner Server\g_gametype\0\mapname\q3dm17`,
+				`  1:50 ShutdownGame:`,
+			},
+			out: []*Event{
+				{Type: EventGameStart, GameID: "1", Time: "0:00"},
+				{Type: EventKill, GameID: "1", Time: "0:10", Killer: "<world>", Dead: "Isgalamido", Means: "MOD_TRIGGER_HURT"},
+				{Type: EventGameEnd, GameID: "1", Time: "1:47"},
+				{Type: EventGameStart, GameID: "2", Time: "1:47"},
+				{Type: EventGameEnd, GameID: "2", Time: "1:50"},
+			},
+		},
+		{
+			description: "a capture the flag game",
+			in: []string{
+				`  0:00 InitGame: \sv_hostname\Code Miner Server\g_gametype\4\mapname\q3ctf1`,
+				`  0:01 ClientUserinfoChanged: 2 n\Isgalamido\t\1\model\xian/default`,
+				`  0:02 ClientUserinfoChanged: 3 n\Dono da Bola\t\2\model\sarge`,
+				`  0:10 Item: 2 team_CTF_blueflag`,
+				`  0:15 Kill: 3 2 10: Dono da Bola killed Isgalamido by MOD_RAILGUN`,
+				`  0:20 Item: 3 team_CTF_blueflag`,
+				`  0:30 ShutdownGame:`,
+			},
+			out: []*Event{
+				{Type: EventGameStart, GameID: "1", Time: "0:00"},
+				{Type: EventFlagPickup, GameID: "1", Time: "0:10", Player: "Isgalamido", Team: TeamBlue},
+				{Type: EventKill, GameID: "1", Time: "0:15", Killer: "Dono da Bola", Dead: "Isgalamido", Means: "MOD_RAILGUN"},
+				{Type: EventCarrierKill, GameID: "1", Time: "0:15", Killer: "Dono da Bola", Dead: "Isgalamido", Team: TeamBlue},
+				{Type: EventFlagReturn, GameID: "1", Time: "0:20", Player: "Dono da Bola", Team: TeamBlue},
+				{Type: EventGameEnd, GameID: "1", Time: "0:30"},
+			},
+		},
+		{
+			description: "a player renamed",
+			in: []string{
+				`  0:00 InitGame: \sv_hostname\Code Miner Server\g_gametype\0\mapname\q3dm17`,
+				` 20:38 ClientUserinfoChanged: 2 n\Isgalamido\t\0\model\uriel/zael\hmodel\uriel/zael`,
+				` 20:38 ClientBegin: 2`,
+				` 20:39 ClientUserinfoChanged: 2 n\Isgalamido\t\0\model\sarge\hmodel\sarge`,
+				` 20:40 ClientUserinfoChanged: 2 n\Mocinha\t\0\model\sarge\hmodel\sarge`,
+				` 20:54 Kill: 1022 2 22: <world> killed Mocinha by MOD_TRIGGER_HURT`,
+			},
+			out: []*Event{
+				{Type: EventGameStart, GameID: "1", Time: "0:00"},
+				{Type: EventJoin, GameID: "1", Time: "20:38", Player: "Isgalamido"},
+				{Type: EventRename, GameID: "1", Time: "20:40", Player: "Mocinha", Previous: "Isgalamido"},
+				{Type: EventKill, GameID: "1", Time: "20:54", Killer: "<world>", Dead: "Mocinha", Means: "MOD_TRIGGER_HURT"},
+			},
+		},
+		{
+			description: "a client id reused by another player",
+			in: []string{
+				`  0:00 InitGame: \sv_hostname\Code Miner Server\g_gametype\0\mapname\q3dm17`,
+				` 20:38 ClientConnect: 2`,
+				` 20:38 ClientUserinfoChanged: 2 n\Isgalamido\t\0\model\uriel/zael\hmodel\uriel/zael`,
+				` 20:38 ClientBegin: 2`,
+				` 20:39 ClientDisconnect: 2`,
+				` 20:40 ClientConnect: 2`,
+				` 20:40 ClientUserinfoChanged: 2 n\Mocinha\t\0\model\sarge\hmodel\sarge`,
+				` 20:40 ClientBegin: 2`,
+			},
+			out: []*Event{
+				{Type: EventGameStart, GameID: "1", Time: "0:00"},
+				{Type: EventJoin, GameID: "1", Time: "20:38", Player: "Isgalamido"},
+				{Type: EventJoin, GameID: "1", Time: "20:40", Player: "Mocinha"},
+			},
+		},
+		{
+			description: "events before any game are ignored",
+			in: []string{
+				` 20:38 ClientUserinfoChanged: 2 n\Isgalamido\t\0\model\uriel/zael\hmodel\uriel/zael`,
+				` 20:38 ClientBegin: 2`,
+				` 20:54 Kill: 1022 2 22: <world> killed Isgalamido by MOD_TRIGGER_HURT`,
+				` 20:55 ShutdownGame:`,
+			},
+			out: nil,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			p := NewEventParser()
+			var es []*Event
+			for _, l := range tc.in {
+				es = append(es, p.Parse(l)...)
+			}
+			if !reflect.DeepEqual(es, tc.out) {
+				t.Errorf("was expecting %v, but returns %v", tc.out, es)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+	"time"
+)
+
+// Follow reads the lines from r as they are written, like a `tail -f`, calling fn
+// for every complete line until the context is done or the reader fails
+func Follow(ctx context.Context, r io.Reader, interval time.Duration, fn func(line string)) error {
+	br := bufio.NewReader(r)
+
+	// keeps the content of a line that was not completely written yet
+	partial := ""
+	for {
+		s, err := br.ReadString('\n')
+		partial += s
+
+		if err == nil {
+			fn(strings.TrimRight(partial, "\r\n"))
+			partial = ""
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+
+		// wait for new content to be appended
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
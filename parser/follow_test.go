@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFollow(t *testing.T) {
+	r, w := io.Pipe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lines := make(chan string)
+	done := make(chan error)
+	go func() {
+		done <- Follow(ctx, r, time.Millisecond, func(line string) {
+			lines <- line
+		})
+	}()
+
+	// the second line is written in two parts and should be delivered only once complete
+	go func() {
+		w.Write([]byte("  0:00 InitGame: \\mapname\\q3dm17\n"))
+		w.Write([]byte("  0:10 Kill: 1022 2 22: <world> "))
+		w.Write([]byte("killed Isgalamido by MOD_TRIGGER_HURT\r\n"))
+	}()
+
+	expected := []string{
+		`  0:00 InitGame: \mapname\q3dm17`,
+		`  0:10 Kill: 1022 2 22: <world> killed Isgalamido by MOD_TRIGGER_HURT`,
+	}
+	var received []string
+	for range expected {
+		received = append(received, <-lines)
+	}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("was expecting %v, but returns %v", expected, received)
+	}
+
+	w.Close()
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("was expecting %v error, but returns %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,54 @@
+//go:build go1.18
+// +build go1.18
+
+package parser_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bgildson/enext-challenge/loggen"
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// seedLines adds the lines of a generated log to the fuzz corpus
+func seedLines(f *testing.F) []string {
+	lines := loggen.Lines(loggen.Generate(loggen.Config{Seed: 1, Games: 2, Players: 3, Kills: 5}))
+	lines = append(lines,
+		"",
+		" 26  0:00 ------------------------------------------------------------",
+		"  0:00 Kill: 1022 2 22: <world> killed Dono killed by by MOD_TRIGGER_HURT",
+		"  0:00 Kill: 2 3: killed by",
+	)
+	return lines
+}
+
+func FuzzLineClassification(f *testing.F) {
+	for _, l := range seedLines(f) {
+		f.Add(l)
+	}
+
+	f.Fuzz(func(t *testing.T, text string) {
+		l := parser.NewLine(text)
+
+		k := l.AsKill()
+		if k != nil && l.Keyword() != "Kill" {
+			t.Errorf("was expecting a kill only from Kill lines, but returns %v from %q", k, text)
+		}
+		if l.IsStartGame() != (l.Keyword() == "InitGame") {
+			t.Errorf("was expecting a game start only from InitGame lines, but returns %v from %q", l.IsStartGame(), text)
+		}
+	})
+}
+
+func FuzzProcessLines(f *testing.F) {
+	f.Add(strings.Join(seedLines(f), "\n"))
+
+	f.Fuzz(func(t *testing.T, log string) {
+		lines := strings.Split(log, "\n")
+
+		var diags parser.Diagnostics
+		games := parser.ProcessLinesParallel(lines, parser.SequentialIDs, 2, &diags)
+		checkInvariants(t, lines, games)
+	})
+}
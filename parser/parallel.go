@@ -0,0 +1,240 @@
+package parser
+
+import (
+	"strings"
+	"sync"
+)
+
+// gameStarts returns the index of every InitGame line
+func gameStarts(lines []string) []int {
+	var starts []int
+	for i, text := range lines {
+		if NewLine(text).IsStartGame() {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
+
+// SplitGames separates the log lines in the lines of every game, each one starting with its InitGame line,
+// the lines before the first game does not belong to any game and are discarded
+func SplitGames(lines []string) [][]string {
+	starts := gameStarts(lines)
+
+	var games [][]string
+	for i, start := range starts {
+		end := len(lines)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		games = append(games, lines[start:end])
+	}
+
+	return games
+}
+
+// chunk is a part of the log processed at once, the lines of a game or the lines before the first game
+type chunk struct {
+	lines    []string
+	index    int
+	game     *Game
+	shutdown bool
+	diags    Diagnostics
+}
+
+// process handles the chunk lines, a chunk starting with an InitGame line generates a game
+// and the lines not understood or out of sequence are diagnosed when diagnose is true
+func (c *chunk) process(diagnose bool) {
+	var ds *Diagnostics
+	if diagnose {
+		ds = &c.diags
+	}
+
+	// the names of the clients, to resolve the players of the kills, and the teams of the players
+	names := map[string]string{}
+	teams := map[string]string{}
+
+	// the flags are followed only in capture the flag games
+	var flags *ctfTracker
+	var play *playTracker
+
+	running := false
+	for i, text := range c.lines {
+		n := c.index + i + 1
+		if diagnose {
+			if reason := lineReason(text); reason != "" {
+				ds.Add(n, text, reason)
+			}
+		}
+
+		clock, keyword, payload, _ := splitLine(text)
+		if play != nil {
+			play.tick(clock)
+		}
+		if flags != nil {
+			for _, e := range flags.next(keyword) {
+				c.game.AddCTFEvent(e)
+			}
+		}
+
+		switch keyword {
+		case "InitGame":
+			c.game = NewGameEmpty()
+			c.game.GameType = gameType(payload)
+			running = true
+			play = newPlayTracker()
+			play.tick(clock)
+			if c.game.GameType == GameTypeCTF {
+				flags = newCTFTracker()
+			}
+
+		case "ClientUserinfoChanged":
+			id, name, ok := userinfoName(payload)
+			if !ok {
+				continue
+			}
+			if previous, ok := names[id]; ok && previous != name && running {
+				play.rename(c.game, id, previous)
+			}
+			names[id] = name
+			if model, ok := userinfoBot(payload); ok && running {
+				c.game.AddBot(name, model)
+			}
+			if team, ok := userinfoTeam(payload); ok && flags != nil {
+				flags.setTeam(id, team)
+			}
+
+			// the clients announce their info many times, only a different team is a change
+			if team, ok := userinfoTeam(payload); ok && running && c.game.IsTeamGame() && teams[name] != team {
+				teams[name] = team
+				c.game.SetTeam(name, team, clock)
+			}
+
+		case "ClientBegin":
+			if running {
+				play.join(strings.TrimSpace(payload))
+			}
+
+		case "ClientDisconnect":
+			if running {
+				id := strings.TrimSpace(payload)
+				play.leave(c.game, id, names[id])
+			}
+
+		case "red":
+			if running && c.game.IsTeamGame() {
+				c.game.TeamScores = teamScores(payload)
+			}
+
+		case "ShutdownGame":
+			if !running {
+				ds.Add(n, text, ReasonShutdownOutsideGame)
+			}
+			if running {
+				play.end(c.game, names)
+			}
+			running = false
+			c.shutdown = true
+			flags = nil
+
+		case "Kill":
+			k, ok := parseKill(payload, names)
+			if !ok {
+				ds.Add(n, text, ReasonMalformedKill)
+				continue
+			}
+			if !running {
+				ds.Add(n, text, ReasonKillOutsideGame)
+				continue
+			}
+			k.Time = clock
+			if c.game.IsTeamGame() {
+				c.game.AddTeamKill(&k, teams[k.Killer], teams[k.Dead])
+			} else {
+				c.game.AddKill(&k)
+			}
+		}
+
+		if flags != nil {
+			for _, e := range flags.parse(clock, keyword, payload, names) {
+				c.game.AddCTFEvent(e)
+			}
+		}
+	}
+
+	// a flag picked up in the last line of the game
+	if flags != nil {
+		for _, e := range flags.next("") {
+			c.game.AddCTFEvent(e)
+		}
+	}
+
+	// the games without shutdown end in their last line
+	if running {
+		play.end(c.game, names)
+	}
+}
+
+// ProcessLinesParallel works like ProcessLinesWithIDs, splitting the lines on the game boundaries to
+// process the games concurrently with many workers, the games are returned in the log order and the ids
+// are generated in that order too, so the result is the same of the sequential processing.
+// When diags is informed, the lines not understood or out of sequence are collected in the log order
+func ProcessLinesParallel(lines []string, ids IDGenerator, workers int, diags *Diagnostics) []*Game {
+	starts := gameStarts(lines)
+
+	// the lines before the first game are processed only to be diagnosed
+	first := len(lines)
+	if len(starts) > 0 {
+		first = starts[0]
+	}
+	chunks := []*chunk{{lines: lines[:first]}}
+	for i, start := range starts {
+		end := len(lines)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		chunks = append(chunks, &chunk{lines: lines[start:end], index: start})
+	}
+
+	if workers <= 1 {
+		for _, c := range chunks {
+			c.process(diags != nil)
+		}
+	} else {
+		jobs := make(chan *chunk)
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for c := range jobs {
+					c.process(diags != nil)
+				}
+			}()
+		}
+		for _, c := range chunks {
+			jobs <- c
+		}
+		close(jobs)
+		wg.Wait()
+	}
+
+	var gs []*Game
+	for i, c := range chunks {
+		// a game without a ShutdownGame is closed when the next one starts
+		if i > 1 && !chunks[i-1].shutdown {
+			diags.Add(c.index+1, c.lines[0], ReasonGameWithoutShutdown)
+		}
+		if diags != nil {
+			*diags = append(*diags, c.diags...)
+		}
+
+		// the generators can depend on the previous games, so the ids are generated in order
+		if c.game != nil {
+			c.game.ID = ids(len(gs)+1, c.lines)
+			gs = append(gs, c.game)
+		}
+	}
+
+	return gs
+}
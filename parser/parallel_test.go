@@ -0,0 +1,141 @@
+package parser
+
+import (
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSplitGames(t *testing.T) {
+	tt := []struct {
+		description string
+		in          []string
+		out         [][]string
+	}{
+		{
+			description: "without games",
+			in:          []string{"  0:00 ------------------------------------------------------------"},
+			out:         nil,
+		},
+		{
+			description: "lines before the first game",
+			in: []string{
+				"  0:00 ClientConnect: 2",
+				"  0:01 InitGame: \\sv_hostname\\one",
+				"  0:02 Kill: 1022 2 22: <world> killed Isgalamido by MOD_TRIGGER_HURT",
+				"  1:00 InitGame: \\sv_hostname\\two",
+			},
+			out: [][]string{
+				{
+					"  0:01 InitGame: \\sv_hostname\\one",
+					"  0:02 Kill: 1022 2 22: <world> killed Isgalamido by MOD_TRIGGER_HURT",
+				},
+				{"  1:00 InitGame: \\sv_hostname\\two"},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			if r := SplitGames(tc.in); !reflect.DeepEqual(r, tc.out) {
+				t.Errorf("was expecting %v, but returns %v", tc.out, r)
+			}
+		})
+	}
+}
+
+func readGamesLog(tb testing.TB) []string {
+	b, err := ioutil.ReadFile("../games.log")
+	if err != nil {
+		tb.Fatalf("could not read the games log: %v", err)
+	}
+	return strings.Split(string(b), "\n")
+}
+
+func TestProcessLinesParallel(t *testing.T) {
+	lines := readGamesLog(t)
+
+	for _, workers := range []int{0, 1, 2, 4, 64} {
+		expected := ProcessLinesWithIDs(lines, NewContentIDGenerator())
+		r := ProcessLinesParallel(lines, NewContentIDGenerator(), workers, nil)
+		if !reflect.DeepEqual(r, expected) {
+			t.Errorf("was expecting the same games of the sequential processing with %d workers", workers)
+		}
+	}
+
+	if r := ProcessLinesParallel(nil, SequentialIDs, 4, nil); r != nil {
+		t.Errorf("was expecting no games, but returns %v", r)
+	}
+}
+
+func TestProcessLinesParallelDiagnostics(t *testing.T) {
+	lines := []string{
+		"  0:00 Kill: 1022 2 22: <world> killed Isgalamido by MOD_TRIGGER_HURT",
+		"  0:01 InitGame: \\sv_hostname\\one",
+		"  0:02 Kill: 1022 2 22: <world> killed Isgalamido by MOD_TRIGGER_HURT",
+		"  0:03 Kill: 1022 2 22: <world> killed Isgalamido",
+		"  0:04 ShutdownGame:",
+		"  0:05 Kill: 2 3 7: Isgalamido killed Mocinha by MOD_ROCKET_SPLASH",
+		"  0:06 ShutdownGame:",
+		"  0:00 ------------------------------------------------------------",
+		"  1:00 InitGame: \\sv_hostname\\two",
+		"  1:01 Teleport: 2",
+		"  2:00 InitGame: \\sv_hostname\\three",
+	}
+	out := []string{
+		"line 1: kill outside of a game",
+		"line 4: malformed kill",
+		"line 6: kill outside of a game",
+		"line 7: shutdown outside of a game",
+		"line 10: unknown event",
+		"line 11: game started before the previous one shutdown",
+	}
+
+	for _, workers := range []int{1, 4} {
+		var ds Diagnostics
+		gs := ProcessLinesParallel(lines, SequentialIDs, workers, &ds)
+
+		var r []string
+		for _, d := range ds {
+			r = append(r, fmt.Sprintf("%s: %s", d.Location(), d.Reason))
+		}
+		if !reflect.DeepEqual(r, out) {
+			t.Errorf("was expecting %v with %d workers, but returns %v", out, workers, r)
+		}
+		if len(gs) != 3 || gs[0].TotalKills != 1 {
+			t.Errorf("was expecting 3 games with 1 kill in the first, but returns %v", gs)
+		}
+	}
+}
+
+// largeLog repeats the games log to simulate an archive of many logs
+func largeLog(b *testing.B) []string {
+	lines := readGamesLog(b)
+
+	var large []string
+	for i := 0; i < 10; i++ {
+		large = append(large, lines...)
+	}
+	return large
+}
+
+func BenchmarkProcessLinesLarge(b *testing.B) {
+	lines := largeLog(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ProcessLines(lines)
+	}
+}
+
+func BenchmarkProcessLinesParallel(b *testing.B) {
+	lines := largeLog(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ProcessLinesParallel(lines, SequentialIDs, runtime.NumCPU(), nil)
+	}
+}
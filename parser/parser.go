@@ -1,14 +1,23 @@
 package parser
 
 import (
-	"regexp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"strconv"
+	"strings"
 )
 
 // Kill represents a kill in game
 type Kill struct {
-	Killer string
-	Dead   string
+	Killer string `json:"killer"`
+	Dead   string `json:"dead"`
+	Means  string `json:"means,omitempty"`
+	// Time is the game clock of the kill, like 20:54
+	Time string `json:"time,omitempty"`
+	// Friendly is a kill of a teammate, which costs a point to the killer
+	Friendly bool `json:"friendly,omitempty"`
 }
 
 // Line works like a façade adapter improving cast for an line while abstract the implementation
@@ -23,34 +32,135 @@ func NewLine(text string) *Line {
 	}
 }
 
+// Keyword returns the event keyword of the line, like InitGame or Kill,
+// when the line does not follow the log format, returns an empty string
+func (l *Line) Keyword() string {
+	_, keyword, _, _ := splitLine(l.line)
+	return keyword
+}
+
 // AsKill try to handle the line as a kill
 // when is not possible, returns nil
 func (l *Line) AsKill() *Kill {
-	re := regexp.MustCompile(`.*Kill:.*: (.*) killed (.*) by.*`)
-
-	match := re.FindStringSubmatch(l.line)
-	if match == nil {
+	var k Kill
+	if !l.ParseKill(&k) {
 		return nil
 	}
+	return &k
+}
+
+// ParseKill try to handle the line as a kill, filling k without allocating,
+// when is not possible, returns false and k is not changed
+func (l *Line) ParseKill(k *Kill) bool {
+	clock, keyword, payload, _ := splitLine(l.line)
+	if keyword != "Kill" {
+		return false
+	}
 
-	return &Kill{
-		Killer: match[1],
-		Dead:   match[2],
+	kill, ok := parseKill(payload, nil)
+	if !ok {
+		return false
 	}
+	kill.Time = clock
+	*k = kill
+	return true
 }
 
 // IsStartGame verify if the line indicates a starting new game
 func (l *Line) IsStartGame() bool {
-	m, _ := regexp.MatchString(`.* InitGame: .*`, l.line)
-	return m
+	return l.Keyword() == "InitGame"
+}
+
+// worldID is the client id of the kills caused by the map, like falling or drowning
+const worldID = "1022"
+
+// parseKill extracts the killer and the dead from a Kill payload, like
+// "1022 2 22: <world> killed Isgalamido by MOD_TRIGGER_HURT". The players are resolved by
+// their client ids with the names table, because the names can contain " killed " and " by ",
+// the text is used only for the clients not found there
+func parseKill(payload string, names map[string]string) (Kill, bool) {
+	i := strings.Index(payload, ": ")
+	if i < 0 {
+		return Kill{}, false
+	}
+	killerID, deadID, ok := killIDs(payload[:i])
+	text := payload[i+len(": "):]
+
+	// the means of death never has spaces, so the last " by" separates it
+	by := strings.LastIndex(text, " by")
+	if by < 0 {
+		return Kill{}, false
+	}
+	means := strings.TrimSpace(text[by+len(" by"):])
+	text = text[:by]
+
+	// the text is verified even for the known clients, a line without it is not a kill
+	killed := strings.LastIndex(text, " killed ")
+	if killed < 0 {
+		return Kill{}, false
+	}
+
+	var killer, dead string
+	if ok {
+		killer, dead = names[killerID], names[deadID]
+		if killerID == worldID {
+			killer = "<world>"
+		}
+	}
+
+	switch {
+	case killer != "" && dead != "":
+	case killer != "" && strings.HasPrefix(text, killer+" killed "):
+		dead = text[len(killer)+len(" killed "):]
+	case dead != "" && strings.HasSuffix(text, " killed "+dead):
+		killer = text[:len(text)-len(dead)-len(" killed ")]
+	default:
+		killer, dead = text[:killed], text[killed+len(" killed "):]
+	}
+
+	return Kill{
+		Killer: killer,
+		Dead:   dead,
+		Means:  means,
+	}, true
+}
+
+// killIDs returns the first two client ids of a Kill payload, like "1022 2 22", the killer and the dead
+func killIDs(text string) (killer, dead string, ok bool) {
+	killer, rest := nextField(text)
+	dead, _ = nextField(rest)
+	return killer, dead, dead != ""
 }
 
-// Game represents the game stats
+// nextField returns the first field of the text separated by spaces and the text after it
+func nextField(text string) (field, rest string) {
+	text = strings.TrimLeft(text, " \t")
+	i := strings.IndexAny(text, " \t")
+	if i < 0 {
+		return text, ""
+	}
+	return text[:i], text[i:]
+}
+
+// Game represents the game stats,
+// the team fields are filled only in team games, the bots have the model of the players detected as bots
+// and the play time has the seconds every player played, from the ClientBegin to the disconnection or the game end
 type Game struct {
-	ID         string         `json:"id"`
-	TotalKills int            `json:"total_kills"`
-	Players    []string       `json:"players"`
-	Kills      map[string]int `json:"kills"`
+	ID           string                  `json:"id"`
+	Server       string                  `json:"server,omitempty"`
+	GameType     string                  `json:"game_type,omitempty"`
+	TotalKills   int                     `json:"total_kills"`
+	Players      []string                `json:"players"`
+	Kills        map[string]int          `json:"kills"`
+	TeamChanges  []*TeamChange           `json:"team_changes,omitempty"`
+	TeamKills    map[string]int          `json:"team_kills,omitempty"`
+	TeamScores   map[string]int          `json:"team_scores,omitempty"`
+	FriendlyFire map[string]int          `json:"friendly_fire,omitempty"`
+	CTF          map[string]*CTFStats    `json:"ctf,omitempty"`
+	Weapons      map[string]*WeaponStats `json:"weapons,omitempty"`
+	KillSequence []*Kill                 `json:"kill_sequence,omitempty"`
+	Bots         map[string]string       `json:"bots,omitempty"`
+	PlayTime     map[string]int          `json:"play_time,omitempty"`
 }
 
 // NewGameEmpty creates a new Game instance
@@ -63,6 +173,13 @@ func NewGameEmpty() *Game {
 	}
 }
 
+// SetServer labels the game with the server that generated it,
+// prefixing the id to keep it unique between the logs of many servers
+func (g *Game) SetServer(server string) {
+	g.Server = server
+	g.ID = fmt.Sprintf("%s-%s", server, g.ID)
+}
+
 // PlayerExists verify if just exists a player
 func (g *Game) PlayerExists(player string) bool {
 	for _, p := range g.Players {
@@ -94,6 +211,9 @@ func (g *Game) AddKill(k *Kill) {
 	// try to add dead player
 	g.AddPlayer(k.Dead)
 
+	g.AddWeaponKill(k)
+	g.addToSequence(k)
+
 	// when the killer and the dead was the same player, does nothing
 	if k.Killer == k.Dead {
 		return
@@ -107,33 +227,104 @@ func (g *Game) AddKill(k *Kill) {
 	}
 }
 
-// ProcessLines takes as input the log lines, process it and return stat games
-func ProcessLines(lines []string) []*Game {
-	var gs []*Game
+// IDGenerator creates the id of a game from its sequence number in the log and its lines
+type IDGenerator func(seq int, lines []string) string
 
-	var g *Game
-	currentID := 1
-	for _, text := range lines {
-		l := NewLine(text)
+// SequentialIDs identifies the games by the order they appear in the log
+func SequentialIDs(seq int, lines []string) string {
+	return strconv.Itoa(seq)
+}
 
-		if l.IsStartGame() {
-			if g != nil {
-				gs = append(gs, g)
-			}
+// contentIDLines is how many lines from the game beginning compose its content id
+const contentIDLines = 8
 
-			g = NewGameEmpty()
-			g.ID = strconv.Itoa(currentID)
-			currentID++
-		}
+// ContentIDs derives the game ids from their content, the InitGame line, with the game clock
+// and the server settings, and the lines following it, so the same game keeps its id even
+// when the games before it change. Games with the same beginning are distinguished by the
+// order they appear, Seen keeps how many times every content was found to allow resuming
+type ContentIDs struct {
+	Seen map[string]int `json:"seen"`
+}
+
+// NewContentIDs creates a new ContentIDs instance
+func NewContentIDs() *ContentIDs {
+	return &ContentIDs{
+		Seen: map[string]int{},
+	}
+}
+
+// NewContentIDGenerator creates an IDGenerator for the game contents
+func NewContentIDGenerator() IDGenerator {
+	return NewContentIDs().Generate
+}
 
-		k := l.AsKill()
-		if k != nil {
-			g.AddKill(k)
+// contentIDPart returns the game lines composing its content id, the first contentIDLines lines,
+// ending at the ShutdownGame, so the lines written after the game end do not change its id
+func contentIDPart(lines []string) []string {
+	if len(lines) > contentIDLines {
+		lines = lines[:contentIDLines]
+	}
+	for i, l := range lines {
+		if _, keyword, _, ok := splitLine(l); ok && keyword == "ShutdownGame" {
+			return lines[:i+1]
 		}
 	}
-	if g != nil {
-		gs = append(gs, g)
+	return lines
+}
+
+// ContentIDSettled reports if the content id of the game lines is final, when the game has all the lines
+// composing the id or it was shutdown, the ids of the shorter games still being written change with their next lines
+func ContentIDSettled(lines []string) bool {
+	part := contentIDPart(lines)
+	if len(part) == 0 {
+		return false
+	}
+	if len(part) == contentIDLines {
+		return true
+	}
+	_, keyword, _, ok := splitLine(part[len(part)-1])
+	return ok && keyword == "ShutdownGame"
+}
+
+func contentHash(lines []string) string {
+	lines = contentIDPart(lines)
+
+	h := sha256.New()
+	for _, l := range lines {
+		io.WriteString(h, strings.TrimSpace(l))
+		io.WriteString(h, "\n")
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// Generate creates the id for the game lines
+func (c *ContentIDs) Generate(seq int, lines []string) string {
+	id := contentHash(lines)
+
+	c.Seen[id]++
+	if n := c.Seen[id]; n > 1 {
+		id = fmt.Sprintf("%s-%d", id, n)
 	}
+	return id
+}
+
+// Release forgets one game generated from the lines, used to generate its id again
+func (c *ContentIDs) Release(lines []string) {
+	id := contentHash(lines)
+
+	if c.Seen[id] > 1 {
+		c.Seen[id]--
+	} else {
+		delete(c.Seen, id)
+	}
+}
+
+// ProcessLines takes as input the log lines, process it and return stat games
+func ProcessLines(lines []string) []*Game {
+	return ProcessLinesWithIDs(lines, SequentialIDs)
+}
 
-	return gs
+// ProcessLinesWithIDs works like ProcessLines, using ids to identify the games
+func ProcessLinesWithIDs(lines []string, ids IDGenerator) []*Game {
+	return ProcessLinesParallel(lines, ids, 1, nil)
 }
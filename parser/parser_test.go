@@ -1,7 +1,10 @@
 package parser
 
 import (
+	"fmt"
+	"math/rand"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -15,6 +18,8 @@ func TestLineAsKill(t *testing.T) {
 			out: &Kill{
 				Killer: "<world>",
 				Dead:   "Isgalamido",
+				Means:  "MOD_TRIGGER_HURT",
+				Time:   "21:42",
 			},
 		},
 		{
@@ -22,6 +27,8 @@ func TestLineAsKill(t *testing.T) {
 			out: &Kill{
 				Killer: "Isgalamido",
 				Dead:   "Mocinha",
+				Means:  "MOD_ROCKET_SPLASH",
+				Time:   "22:06",
 			},
 		},
 		{
@@ -44,10 +51,117 @@ func TestLineAsKill(t *testing.T) {
 			if r := l.AsKill(); !reflect.DeepEqual(r, tc.out) {
 				t.Errorf("was expecting %v, but returns %v", tc.out, r)
 			}
+
+			var k Kill
+			if ok := l.ParseKill(&k); ok != (tc.out != nil) || (ok && k != *tc.out) || (!ok && k != Kill{}) {
+				t.Errorf("was expecting %v, but returns %v %v", tc.out, k, ok)
+			}
+		})
+	}
+}
+
+func TestParseKill(t *testing.T) {
+	names := map[string]string{
+		"2": "Dono killed by",
+		"3": "by killed Mocinha",
+		"4": "killed",
+	}
+	tt := []struct {
+		description string
+		in          string
+		out         Kill
+	}{
+		{
+			description: "resolved by the client ids",
+			in:          "2 3 7: Dono killed by killed by killed Mocinha by MOD_ROCKET_SPLASH",
+			out:         Kill{Killer: "Dono killed by", Dead: "by killed Mocinha", Means: "MOD_ROCKET_SPLASH"},
+		},
+		{
+			description: "killed by the world",
+			in:          "1022 4 22: <world> killed killed by MOD_TRIGGER_HURT",
+			out:         Kill{Killer: "<world>", Dead: "killed", Means: "MOD_TRIGGER_HURT"},
+		},
+		{
+			description: "unknown dead",
+			in:          "2 5 7: Dono killed by killed Zeh killed by by MOD_ROCKET",
+			out:         Kill{Killer: "Dono killed by", Dead: "Zeh killed by", Means: "MOD_ROCKET"},
+		},
+		{
+			description: "unknown killer",
+			in:          "5 4 7: Zeh killed Assasinu killed killed by MOD_ROCKET",
+			out:         Kill{Killer: "Zeh killed Assasinu", Dead: "killed", Means: "MOD_ROCKET"},
+		},
+		{
+			description: "unknown players",
+			in:          "5 6 7: Zeh killed Assasinu Credi by MOD_ROCKET",
+			out:         Kill{Killer: "Zeh", Dead: "Assasinu Credi", Means: "MOD_ROCKET"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			r, ok := parseKill(tc.in, names)
+			if !ok || !reflect.DeepEqual(r, tc.out) {
+				t.Errorf("was expecting %v, but returns %v", tc.out, r)
+			}
 		})
 	}
 }
 
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// TestParseKillAdversarialNames generates games with random names composed by
+// the words of the kill lines and verify every kill is attributed to the right players
+func TestParseKillAdversarialNames(t *testing.T) {
+	words := []string{"killed", "by", "Dono", "da", "Bola", "world", "MOD_ROCKET", ":", "1022", "killed by"}
+	r := rand.New(rand.NewSource(1))
+
+	name := func() string {
+		var parts []string
+		for i := 0; i < 1+r.Intn(4); i++ {
+			parts = append(parts, words[r.Intn(len(words))])
+		}
+		return strings.Join(parts, " ")
+	}
+
+	for i := 0; i < 500; i++ {
+		// the players are identified by their names in the games, so they should be unique
+		var players []string
+		for len(players) < 3 {
+			if p := name(); !contains(players, p) {
+				players = append(players, p)
+			}
+		}
+		lines := []string{`  0:00 InitGame: \sv_hostname\Code Miner Server`}
+		for id, p := range players {
+			lines = append(lines, fmt.Sprintf(`  0:01 ClientUserinfoChanged: %d n\%s\t\0`, id+2, p))
+		}
+
+		expected := map[string]int{}
+		for k := 0; k < 10; k++ {
+			killer, dead := r.Intn(len(players)), r.Intn(len(players))
+			lines = append(lines, fmt.Sprintf("  0:02 Kill: %d %d 7: %s killed %s by MOD_ROCKET", killer+2, dead+2, players[killer], players[dead]))
+			expected[players[killer]] += 0
+			expected[players[dead]] += 0
+			if killer != dead {
+				expected[players[killer]]++
+			}
+		}
+
+		gs := ProcessLines(lines)
+		if len(gs) != 1 || !reflect.DeepEqual(gs[0].Kills, expected) {
+			t.Fatalf("was expecting %v for the players %q, but returns %v", expected, players, gs[0].Kills)
+		}
+	}
+}
+
 func TestLineIsStartGame(t *testing.T) {
 	tt := []struct {
 		in  string
@@ -474,6 +588,21 @@ func TestProcessLines(t *testing.T) {
 						"Isgalamido": -2,
 						"Mocinha":    0,
 					},
+					Weapons: map[string]*WeaponStats{
+						"Isgalamido": {
+							Kills:  map[string]int{"MOD_ROCKET_SPLASH": 1},
+							Deaths: map[string]int{"MOD_TRIGGER_HURT": 3, "MOD_ROCKET_SPLASH": 1},
+						},
+						"Mocinha": {Deaths: map[string]int{"MOD_ROCKET_SPLASH": 1}},
+					},
+					KillSequence: []*Kill{
+						{Killer: "<world>", Dead: "Isgalamido", Means: "MOD_TRIGGER_HURT", Time: "20:54"},
+						{Killer: "<world>", Dead: "Isgalamido", Means: "MOD_TRIGGER_HURT", Time: "21:07"},
+						{Killer: "<world>", Dead: "Isgalamido", Means: "MOD_TRIGGER_HURT", Time: "21:42"},
+						{Killer: "Isgalamido", Dead: "Mocinha", Means: "MOD_ROCKET_SPLASH", Time: "22:06"},
+						{Killer: "Isgalamido", Dead: "Isgalamido", Means: "MOD_ROCKET_SPLASH", Time: "22:18"},
+					},
+					PlayTime: map[string]int{"Isgalamido": 102, "Mocinha": 18},
 				},
 			},
 		},
@@ -556,6 +685,21 @@ func TestProcessLines(t *testing.T) {
 						"Isgalamido": -2,
 						"Mocinha":    0,
 					},
+					Weapons: map[string]*WeaponStats{
+						"Isgalamido": {
+							Kills:  map[string]int{"MOD_ROCKET_SPLASH": 1},
+							Deaths: map[string]int{"MOD_TRIGGER_HURT": 3, "MOD_ROCKET_SPLASH": 1},
+						},
+						"Mocinha": {Deaths: map[string]int{"MOD_ROCKET_SPLASH": 1}},
+					},
+					KillSequence: []*Kill{
+						{Killer: "<world>", Dead: "Isgalamido", Means: "MOD_TRIGGER_HURT", Time: "20:54"},
+						{Killer: "<world>", Dead: "Isgalamido", Means: "MOD_TRIGGER_HURT", Time: "21:07"},
+						{Killer: "<world>", Dead: "Isgalamido", Means: "MOD_TRIGGER_HURT", Time: "21:42"},
+						{Killer: "Isgalamido", Dead: "Mocinha", Means: "MOD_ROCKET_SPLASH", Time: "22:06"},
+						{Killer: "Isgalamido", Dead: "Isgalamido", Means: "MOD_ROCKET_SPLASH", Time: "22:18"},
+					},
+					PlayTime: map[string]int{"Isgalamido": 102, "Mocinha": 18},
 				},
 				{
 					ID:         "2",
@@ -566,6 +710,21 @@ func TestProcessLines(t *testing.T) {
 						"Dono da Bola": 0,
 						"Zeh":          0,
 					},
+					Weapons: map[string]*WeaponStats{
+						"Isgalamido": {Deaths: map[string]int{"MOD_TRIGGER_HURT": 1, "MOD_FALLING": 1}},
+						"Dono da Bola": {
+							Kills:  map[string]int{"MOD_ROCKET": 1},
+							Deaths: map[string]int{"MOD_FALLING": 1},
+						},
+						"Zeh": {Deaths: map[string]int{"MOD_ROCKET": 1}},
+					},
+					KillSequence: []*Kill{
+						{Killer: "<world>", Dead: "Isgalamido", Means: "MOD_TRIGGER_HURT", Time: "2:00"},
+						{Killer: "<world>", Dead: "Dono da Bola", Means: "MOD_FALLING", Time: "2:04"},
+						{Killer: "<world>", Dead: "Isgalamido", Means: "MOD_FALLING", Time: "2:04"},
+						{Killer: "Dono da Bola", Dead: "Zeh", Means: "MOD_ROCKET", Time: "2:11"},
+					},
+					PlayTime: map[string]int{"Isgalamido": 28, "Dono da Bola": 28, "Zeh": 28},
 				},
 			},
 		},
@@ -580,3 +739,143 @@ func TestProcessLines(t *testing.T) {
 		})
 	}
 }
+
+func TestGameSetServer(t *testing.T) {
+	g := NewGameEmpty()
+	g.ID = "3"
+	g.SetServer("server1")
+
+	if g.ID != "server1-3" || g.Server != "server1" {
+		t.Errorf("was expecting server1-3 id from server1, but returns %s id from %s", g.ID, g.Server)
+	}
+}
+
+func TestProcessLinesWithIDs(t *testing.T) {
+	game := []string{
+		`  0:00 InitGame: \sv_hostname\Code Miner Server\g_gametype\0\mapname\q3dm17`,
+		`  0:25 ClientConnect: 2`,
+		`  0:25 ClientUserinfoChanged: 2 n\Isgalamido\t\0\model\xian/default\hmodel\xian/default`,
+		`  0:30 Kill: 1022 2 22: <world> killed Isgalamido by MOD_TRIGGER_HURT`,
+		`  0:40 ShutdownGame:`,
+	}
+	other := []string{
+		`  1:47 InitGame: \sv_hostname\Code Miner Server\g_gametype\0\mapname\q3dm17`,
+		`  1:50 ShutdownGame:`,
+	}
+
+	ids := func(lines []string) []string {
+		var r []string
+		for _, g := range ProcessLinesWithIDs(lines, NewContentIDGenerator()) {
+			r = append(r, g.ID)
+		}
+		return r
+	}
+
+	original := ids(game)
+	if len(original) != 1 || len(original[0]) != 12 {
+		t.Fatalf("was expecting one id with 12 chars, but returns %v", original)
+	}
+
+	t.Run("keeps the id when games are added before", func(t *testing.T) {
+		r := ids(append(append([]string{`  0:00 ------`}, other...), game...))
+		if r[1] != original[0] {
+			t.Errorf("was expecting %v, but returns %v", original[0], r[1])
+		}
+	})
+
+	t.Run("distinguishes games with the same beginning", func(t *testing.T) {
+		r := ids(append(append([]string{}, game...), game...))
+		if out := []string{original[0], original[0] + "-2"}; !reflect.DeepEqual(r, out) {
+			t.Errorf("was expecting %v, but returns %v", out, r)
+		}
+	})
+
+	t.Run("ignores the lines after the shutdown", func(t *testing.T) {
+		r := ids(append(append([]string{}, game...), `  0:41 ------`))
+		if r[0] != original[0] {
+			t.Errorf("was expecting %v, but returns %v", original[0], r[0])
+		}
+	})
+
+	t.Run("settled ids", func(t *testing.T) {
+		long := append([]string{}, game[:4]...)
+		for i := 0; i < contentIDLines; i++ {
+			long = append(long, `  0:35 Item: 2 weapon_rocketlauncher`)
+		}
+		tests := []struct {
+			name  string
+			lines []string
+			out   bool
+		}{
+			{"empty", nil, false},
+			{"short game being written", game[:4], false},
+			{"short game shutdown", game, true},
+			{"long game being written", long, true},
+		}
+		for _, tc := range tests {
+			if r := ContentIDSettled(tc.lines); r != tc.out {
+				t.Errorf("%s: was expecting %v, but returns %v", tc.name, tc.out, r)
+			}
+		}
+	})
+
+	t.Run("sequential ids", func(t *testing.T) {
+		gs := ProcessLinesWithIDs(append(append([]string{}, other...), game...), SequentialIDs)
+		if gs[0].ID != "1" || gs[1].ID != "2" {
+			t.Errorf("was expecting 1 and 2 ids, but returns %v and %v", gs[0].ID, gs[1].ID)
+		}
+	})
+}
+
+var benchmarkLines = []string{
+	`  0:00 InitGame: \sv_floodProtect\1\sv_maxPing\0\sv_minPing\0\sv_maxRate\10000\sv_minRate\0\sv_hostname\Code Miner Server\g_gametype\0\sv_privateClients\2\sv_maxclients\16\sv_allowDownload\0\dmflags\0\fraglimit\20\timelimit\15\g_maxGameClients\0\capturelimit\8\version\ioq3 1.36 linux-x86_64 Apr 12 2009\protocol\68\mapname\q3dm17\gamename\baseq3\g_needpass\0`,
+	`  1:47 ClientUserinfoChanged: 2 n\Dono da Bola\t\0\model\sarge\hmodel\sarge\g_redteam\\g_blueteam\\c1\4\c2\5\hc\95\w\0\l\0\tt\0\tl\0`,
+	"22:04 Item: 2 ammo_rockets",
+	"22:06 Kill: 2 3 7: Isgalamido killed Mocinha by MOD_ROCKET_SPLASH",
+}
+
+// BenchmarkLineParseKill should not allocate, the kill is filled in the struct of the caller,
+// while AsKill allocates the returned kill when it outlives the call
+func BenchmarkLineParseKill(b *testing.B) {
+	b.ReportAllocs()
+	var k Kill
+	for i := 0; i < b.N; i++ {
+		for _, text := range benchmarkLines {
+			NewLine(text).ParseKill(&k)
+		}
+	}
+}
+
+func BenchmarkLineAsKill(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, text := range benchmarkLines {
+			NewLine(text).AsKill()
+		}
+	}
+}
+
+func BenchmarkLineIsStartGame(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, text := range benchmarkLines {
+			NewLine(text).IsStartGame()
+		}
+	}
+}
+
+func BenchmarkProcessLines(b *testing.B) {
+	lines := readGamesLog(b)
+
+	size := 0
+	for _, l := range lines {
+		size += len(l) + 1
+	}
+
+	b.SetBytes(int64(size))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ProcessLines(lines)
+	}
+}
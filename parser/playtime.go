@@ -0,0 +1,68 @@
+package parser
+
+import "time"
+
+// playTracker follows when the clients joined the game, to know how long the players played
+type playTracker struct {
+	joined map[string]int
+	last   int
+}
+
+func newPlayTracker() *playTracker {
+	return &playTracker{joined: map[string]int{}}
+}
+
+// tick keeps the latest clock seen, the players still playing leave there when the game ends,
+// the clocks going back, like in the games interrupted by a server restart, are ignored
+func (t *playTracker) tick(clock string) {
+	d, ok := ParseClock(clock)
+	if s := int(d / time.Second); ok && s > t.last {
+		t.last = s
+	}
+}
+
+// join starts counting the time of the client, the clients begin again on every respawn of the team games
+func (t *playTracker) join(id string) {
+	if _, ok := t.joined[id]; !ok {
+		t.joined[id] = t.last
+	}
+}
+
+// rename adds to the game the time the client played with the previous name, the kills are resolved
+// by the client id to the name of the moment, so the time with the new name is counted apart
+func (t *playTracker) rename(g *Game, id, previous string) {
+	start, ok := t.joined[id]
+	if !ok || previous == "" {
+		return
+	}
+	t.joined[id] = t.last
+	g.AddPlayTime(previous, t.last-start)
+}
+
+// leave adds to the game the time the client played
+func (t *playTracker) leave(g *Game, id, name string) {
+	start, ok := t.joined[id]
+	if !ok || name == "" {
+		return
+	}
+	delete(t.joined, id)
+	g.AddPlayTime(name, t.last-start)
+}
+
+// end adds to the game the time of the clients still playing
+func (t *playTracker) end(g *Game, names map[string]string) {
+	for id := range t.joined {
+		t.leave(g, id, names[id])
+	}
+}
+
+// AddPlayTime adds the seconds played by the player, the players who left as soon as they joined are not kept
+func (g *Game) AddPlayTime(player string, seconds int) {
+	if seconds <= 0 {
+		return
+	}
+	if g.PlayTime == nil {
+		g.PlayTime = map[string]int{}
+	}
+	g.PlayTime[player] += seconds
+}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProcessLinesPlayTime(t *testing.T) {
+	tt := []struct {
+		description string
+		in          []string
+		out         map[string]int
+	}{
+		{
+			description: "players leaving and joining again",
+			in: []string{
+				`  0:00 InitGame: \sv_hostname\Code Miner Server\g_gametype\0\mapname\q3dm17`,
+				`  0:05 ClientUserinfoChanged: 2 n\Isgalamido\t\0`,
+				`  0:05 ClientBegin: 2`,
+				`  0:10 ClientUserinfoChanged: 3 n\Mocinha\t\0`,
+				`  0:10 ClientBegin: 3`,
+				`  0:40 ClientDisconnect: 2`,
+				`  1:00 ClientUserinfoChanged: 2 n\Isgalamido\t\0`,
+				`  1:00 ClientBegin: 2`,
+				`  1:00 ClientBegin: 2`,
+				`  1:30 ShutdownGame:`,
+			},
+			out: map[string]int{"Isgalamido": 65, "Mocinha": 80},
+		},
+		{
+			description: "a player renamed in the game",
+			in: []string{
+				`  0:00 InitGame: \sv_hostname\Code Miner Server\g_gametype\0\mapname\q3dm17`,
+				`  0:05 ClientUserinfoChanged: 2 n\Isgalamido\t\0`,
+				`  0:05 ClientBegin: 2`,
+				`  0:20 ClientUserinfoChanged: 2 n\Isga\t\0`,
+				`  0:30 ClientUserinfoChanged: 2 n\Isga\t\0`,
+				`  0:40 Kill: 2 3 7: Isga killed Mocinha by MOD_ROCKET_SPLASH`,
+				`  0:50 ClientDisconnect: 2`,
+				`  1:00 ShutdownGame:`,
+			},
+			out: map[string]int{"Isgalamido": 15, "Isga": 30},
+		},
+		{
+			description: "a game without shutdown",
+			in: []string{
+				`  0:00 InitGame: \sv_hostname\Code Miner Server\g_gametype\0\mapname\q3dm17`,
+				`  0:05 ClientUserinfoChanged: 2 n\Isgalamido\t\0`,
+				`  0:05 ClientBegin: 2`,
+				`  0:50 Item: 2 weapon_rocketlauncher`,
+				`  0:02 Item: 2 ammo_rockets`,
+			},
+			out: map[string]int{"Isgalamido": 45},
+		},
+		{
+			description: "players leaving as soon as they join",
+			in: []string{
+				`  0:00 InitGame: \sv_hostname\Code Miner Server\g_gametype\0\mapname\q3dm17`,
+				`  0:05 ClientUserinfoChanged: 2 n\Isgalamido\t\0`,
+				`  0:05 ClientBegin: 2`,
+				`  0:05 ClientDisconnect: 2`,
+				`  0:10 ShutdownGame:`,
+			},
+			out: nil,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			games := ProcessLines(tc.in)
+			if len(games) != 1 {
+				t.Fatalf("was expecting 1 game, but returns %d", len(games))
+			}
+			if !reflect.DeepEqual(games[0].PlayTime, tc.out) {
+				t.Errorf("was expecting %v, but returns %v", tc.out, games[0].PlayTime)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package parser_test
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/bgildson/enext-challenge/loggen"
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// checkInvariants verify the properties every processed game should have
+func checkInvariants(t *testing.T, lines []string, games []*parser.Game) {
+	t.Helper()
+
+	chunks := parser.SplitGames(lines)
+	if len(chunks) != len(games) {
+		t.Fatalf("was expecting %d games, but returns %d", len(chunks), len(games))
+	}
+
+	for i, g := range games {
+		// the kills after the ShutdownGame do not belong to the game
+		kills, withMeans := 0, 0
+		for _, l := range chunks[i] {
+			line := parser.NewLine(l)
+			if line.Keyword() == "ShutdownGame" {
+				break
+			}
+			if k := line.AsKill(); k != nil {
+				kills++
+				if k.Means != "" {
+					withMeans++
+				}
+			}
+		}
+		if g.TotalKills != kills {
+			t.Errorf("was expecting %d total kills in the game %s, but returns %d", kills, g.ID, g.TotalKills)
+		}
+
+		// every kill has a dead player, so the deaths sum is the total kills,
+		// only the kills without the means of death are not recorded by weapon
+		deaths := 0
+		for _, s := range g.Weapons {
+			for _, n := range s.Deaths {
+				deaths += n
+			}
+		}
+		if deaths != withMeans {
+			t.Errorf("was expecting %d deaths in the game %s, but returns %d", withMeans, g.ID, deaths)
+		}
+
+		for _, p := range g.Players {
+			if p == "<world>" {
+				t.Errorf("was expecting <world> not to be a player in the game %s", g.ID)
+			}
+		}
+	}
+}
+
+func TestProcessLinesGeneratedLogs(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 50; i++ {
+		c := loggen.Config{
+			Seed:    r.Int63(),
+			Games:   1 + r.Intn(10),
+			Players: r.Intn(16),
+			Kills:   r.Intn(200),
+		}
+		lines := loggen.Lines(loggen.Generate(c))
+
+		checkInvariants(t, lines, parser.ProcessLines(lines))
+	}
+}
+
+func TestProcessLinesChallengeLog(t *testing.T) {
+	b, err := ioutil.ReadFile("../games.log")
+	if err != nil {
+		t.Fatalf("could not read the games log: %v", err)
+	}
+	lines := strings.Split(string(b), "\n")
+
+	checkInvariants(t, lines, parser.ProcessLines(lines))
+}
@@ -0,0 +1,10 @@
+package parser
+
+// addToSequence keeps the kills read from the log, the ones with the game clock, in the order they happened
+func (g *Game) addToSequence(k *Kill) {
+	if k.Time == "" {
+		return
+	}
+	kill := *k
+	g.KillSequence = append(g.KillSequence, &kill)
+}
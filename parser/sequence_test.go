@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGameAddToSequence(t *testing.T) {
+	tt := []struct {
+		description string
+		in          []*Kill
+		out         []*Kill
+	}{
+		{
+			description: "a kill without the game clock",
+			in:          []*Kill{{Killer: "Isgalamido", Dead: "Mocinha", Means: "MOD_RAILGUN"}},
+			out:         nil,
+		},
+		{
+			description: "kills in the order they happened",
+			in: []*Kill{
+				{Killer: "Isgalamido", Dead: "Mocinha", Means: "MOD_RAILGUN", Time: "0:10"},
+				{Killer: "<world>", Dead: "Isgalamido", Means: "MOD_TRIGGER_HURT", Time: "0:12"},
+			},
+			out: []*Kill{
+				{Killer: "Isgalamido", Dead: "Mocinha", Means: "MOD_RAILGUN", Time: "0:10"},
+				{Killer: "<world>", Dead: "Isgalamido", Means: "MOD_TRIGGER_HURT", Time: "0:12"},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			g := NewGameEmpty()
+			for _, k := range tc.in {
+				g.addToSequence(k)
+			}
+			if !reflect.DeepEqual(g.KillSequence, tc.out) {
+				t.Errorf("was expecting %v, but returns %v", tc.out, g.KillSequence)
+			}
+			// the sequence keeps copies, so the kills can be changed after added
+			if len(tc.in) > 0 && len(g.KillSequence) > 0 && tc.in[0] == g.KillSequence[0] {
+				t.Errorf("was expecting a copy of the kill, but returns the same kill")
+			}
+		})
+	}
+}
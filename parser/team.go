@@ -0,0 +1,141 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Game types, from the g_gametype of the InitGame line, free for all games have no type
+const (
+	GameTypeFFA        = ""
+	GameTypeTournament = "tournament"
+	GameTypeSingle     = "single"
+	GameTypeTDM        = "tdm"
+	GameTypeCTF        = "ctf"
+)
+
+var gameTypes = map[string]string{
+	"1": GameTypeTournament,
+	"2": GameTypeSingle,
+	"3": GameTypeTDM,
+	"4": GameTypeCTF,
+}
+
+// Teams, from the t field of the client info
+const (
+	TeamFree      = "free"
+	TeamRed       = "red"
+	TeamBlue      = "blue"
+	TeamSpectator = "spectator"
+)
+
+var teams = map[string]string{
+	"0": TeamFree,
+	"1": TeamRed,
+	"2": TeamBlue,
+	"3": TeamSpectator,
+}
+
+// TeamChange records when a player joined a team
+type TeamChange struct {
+	Player string `json:"player"`
+	Team   string `json:"team"`
+	Time   string `json:"time"`
+}
+
+// infoValue returns the value of a key in an info string, like \g_gametype\4\mapname\q3ctf1
+func infoValue(info, key string) (string, bool) {
+	parts := strings.Split(strings.TrimPrefix(info, `\`), `\`)
+	for i := 0; i+1 < len(parts); i += 2 {
+		if parts[i] == key {
+			return parts[i+1], true
+		}
+	}
+	return "", false
+}
+
+// gameType extracts the game type from an InitGame payload
+func gameType(payload string) string {
+	v, _ := infoValue(payload, "g_gametype")
+	return gameTypes[v]
+}
+
+// userinfoTeam extracts the player team from a ClientUserinfoChanged payload
+func userinfoTeam(payload string) (string, bool) {
+	i := strings.IndexByte(payload, ' ')
+	if i < 0 {
+		return "", false
+	}
+	v, ok := infoValue(payload[i+1:], "t")
+	if !ok {
+		return "", false
+	}
+	t, ok := teams[v]
+	return t, ok
+}
+
+// teamScores extracts the team scores from a "red:8  blue:6" payload, which follows the red keyword
+func teamScores(payload string) map[string]int {
+	scores := map[string]int{}
+
+	fields := strings.Fields(payload)
+	if len(fields) == 0 {
+		return scores
+	}
+	if n, err := strconv.Atoi(fields[0]); err == nil {
+		scores[TeamRed] = n
+	}
+	for _, f := range fields[1:] {
+		if strings.HasPrefix(f, TeamBlue+":") {
+			if n, err := strconv.Atoi(f[len(TeamBlue)+1:]); err == nil {
+				scores[TeamBlue] = n
+			}
+		}
+	}
+	return scores
+}
+
+// IsTeamGame verify if the players are split in teams
+func (g *Game) IsTeamGame() bool {
+	return g.GameType == GameTypeTDM || g.GameType == GameTypeCTF
+}
+
+// SetTeam records the player joining a team
+func (g *Game) SetTeam(player, team, time string) {
+	g.TeamChanges = append(g.TeamChanges, &TeamChange{
+		Player: player,
+		Team:   team,
+		Time:   time,
+	})
+}
+
+// AddTeamKill handles a kill in a team game, a player killing a teammate is a friendly fire,
+// which costs a point like in the game score, the other kills by players count for the killer team
+func (g *Game) AddTeamKill(k *Kill, killerTeam, deadTeam string) {
+	friendly := k.Killer != "<world>" && k.Killer != k.Dead && killerTeam != "" && killerTeam == deadTeam
+	if !friendly {
+		g.AddKill(k)
+		if k.Killer != "<world>" && k.Killer != k.Dead && killerTeam != "" {
+			if g.TeamKills == nil {
+				g.TeamKills = map[string]int{}
+			}
+			g.TeamKills[killerTeam]++
+		}
+		return
+	}
+
+	g.TotalKills++
+	g.AddPlayer(k.Killer)
+	g.AddPlayer(k.Dead)
+	g.Kills[k.Killer]--
+
+	f := *k
+	f.Friendly = true
+	g.AddWeaponKill(&f)
+	g.addToSequence(&f)
+
+	if g.FriendlyFire == nil {
+		g.FriendlyFire = map[string]int{}
+	}
+	g.FriendlyFire[k.Killer]++
+}
@@ -0,0 +1,180 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUserinfoTeam(t *testing.T) {
+	tt := []struct {
+		in  string
+		out string
+		ok  bool
+	}{
+		{in: `2 n\Isgalamido\t\1\model\xian/default`, out: TeamRed, ok: true},
+		{in: `3 n\Dono da Bola\t\2\model\sarge`, out: TeamBlue, ok: true},
+		{in: `4 n\Zeh\t\3`, out: TeamSpectator, ok: true},
+		{in: `5 n\Mocinha\model\sarge`, out: "", ok: false},
+		{in: `invalid`, out: "", ok: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.in, func(t *testing.T) {
+			if r, ok := userinfoTeam(tc.in); r != tc.out || ok != tc.ok {
+				t.Errorf("was expecting %q and %v, but returns %q and %v", tc.out, tc.ok, r, ok)
+			}
+		})
+	}
+}
+
+func TestGameType(t *testing.T) {
+	tt := []struct {
+		in  string
+		out string
+	}{
+		{in: `\sv_hostname\Code Miner Server\g_gametype\0\mapname\q3dm17`, out: GameTypeFFA},
+		{in: `\sv_hostname\Code Miner Server\g_gametype\3\mapname\q3dm17`, out: GameTypeTDM},
+		{in: `\g_gametype\4\mapname\q3ctf1`, out: GameTypeCTF},
+		{in: `\sv_hostname\Code Miner Server`, out: GameTypeFFA},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.in, func(t *testing.T) {
+			if r := gameType(tc.in); r != tc.out {
+				t.Errorf("was expecting %q, but returns %q", tc.out, r)
+			}
+		})
+	}
+}
+
+func TestTeamScores(t *testing.T) {
+	out := map[string]int{TeamRed: 8, TeamBlue: 6}
+	if r := teamScores("8  blue:6"); !reflect.DeepEqual(r, out) {
+		t.Errorf("was expecting %v, but returns %v", out, r)
+	}
+}
+
+func TestGameAddTeamKill(t *testing.T) {
+	tt := []struct {
+		description string
+		kill        *Kill
+		killerTeam  string
+		deadTeam    string
+		out         *Game
+	}{
+		{
+			description: "a kill of an enemy",
+			kill:        &Kill{Killer: "player one", Dead: "player two"},
+			killerTeam:  TeamRed,
+			deadTeam:    TeamBlue,
+			out: &Game{
+				TotalKills: 1,
+				Players:    []string{"player one", "player two"},
+				Kills:      map[string]int{"player one": 1, "player two": 0},
+				TeamKills:  map[string]int{TeamRed: 1},
+			},
+		},
+		{
+			description: "a kill of a teammate",
+			kill:        &Kill{Killer: "player one", Dead: "player two"},
+			killerTeam:  TeamRed,
+			deadTeam:    TeamRed,
+			out: &Game{
+				TotalKills:   1,
+				Players:      []string{"player one", "player two"},
+				Kills:        map[string]int{"player one": -1, "player two": 0},
+				FriendlyFire: map[string]int{"player one": 1},
+			},
+		},
+		{
+			description: "a kill by the world",
+			kill:        &Kill{Killer: "<world>", Dead: "player two"},
+			killerTeam:  "",
+			deadTeam:    TeamBlue,
+			out: &Game{
+				TotalKills: 1,
+				Players:    []string{"player two"},
+				Kills:      map[string]int{"player two": -1},
+			},
+		},
+		{
+			description: "a suicide",
+			kill:        &Kill{Killer: "player one", Dead: "player one"},
+			killerTeam:  TeamRed,
+			deadTeam:    TeamRed,
+			out: &Game{
+				TotalKills: 1,
+				Players:    []string{"player one"},
+				Kills:      map[string]int{"player one": 0},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			g := NewGameEmpty()
+			g.AddTeamKill(tc.kill, tc.killerTeam, tc.deadTeam)
+			if !reflect.DeepEqual(g, tc.out) {
+				t.Errorf("was expecting %v, but returns %v", tc.out, g)
+			}
+		})
+	}
+}
+
+func TestProcessLinesTeamGame(t *testing.T) {
+	lines := []string{
+		`  0:00 InitGame: \sv_hostname\Code Miner Server\g_gametype\4\capturelimit\8\mapname\q3ctf1`,
+		`  0:01 ClientConnect: 2`,
+		`  0:01 ClientUserinfoChanged: 2 n\Isgalamido\t\1\model\xian/default`,
+		`  0:02 ClientConnect: 3`,
+		`  0:02 ClientUserinfoChanged: 3 n\Dono da Bola\t\2\model\sarge`,
+		`  0:03 ClientConnect: 4`,
+		`  0:03 ClientUserinfoChanged: 4 n\Mocinha\t\2\model\sarge`,
+		`  0:04 ClientUserinfoChanged: 4 n\Mocinha\t\2\model\sarge/krusade`,
+		`  0:10 Kill: 2 3 7: Isgalamido killed Dono da Bola by MOD_ROCKET_SPLASH`,
+		`  0:20 Kill: 3 4 10: Dono da Bola killed Mocinha by MOD_RAILGUN`,
+		`  0:30 ClientUserinfoChanged: 4 n\Mocinha\t\1\model\sarge`,
+		`  0:40 Kill: 4 3 10: Mocinha killed Dono da Bola by MOD_RAILGUN`,
+		`  0:50 Exit: Capturelimit hit.`,
+		`  0:50 red:8  blue:6`,
+		`  0:50 ShutdownGame:`,
+	}
+
+	out := []*Game{
+		{
+			ID:         "1",
+			GameType:   GameTypeCTF,
+			TotalKills: 3,
+			Players:    []string{"Isgalamido", "Dono da Bola", "Mocinha"},
+			Kills:      map[string]int{"Isgalamido": 1, "Dono da Bola": -1, "Mocinha": 1},
+			TeamChanges: []*TeamChange{
+				{Player: "Isgalamido", Team: TeamRed, Time: "0:01"},
+				{Player: "Dono da Bola", Team: TeamBlue, Time: "0:02"},
+				{Player: "Mocinha", Team: TeamBlue, Time: "0:03"},
+				{Player: "Mocinha", Team: TeamRed, Time: "0:30"},
+			},
+			TeamKills:    map[string]int{TeamRed: 2},
+			TeamScores:   map[string]int{TeamRed: 8, TeamBlue: 6},
+			FriendlyFire: map[string]int{"Dono da Bola": 1},
+			Weapons: map[string]*WeaponStats{
+				"Isgalamido": {Kills: map[string]int{"MOD_ROCKET_SPLASH": 1}},
+				"Dono da Bola": {
+					Deaths: map[string]int{"MOD_ROCKET_SPLASH": 1, "MOD_RAILGUN": 1},
+				},
+				"Mocinha": {
+					Kills:  map[string]int{"MOD_RAILGUN": 1},
+					Deaths: map[string]int{"MOD_RAILGUN": 1},
+				},
+			},
+			KillSequence: []*Kill{
+				{Killer: "Isgalamido", Dead: "Dono da Bola", Means: "MOD_ROCKET_SPLASH", Time: "0:10"},
+				{Killer: "Dono da Bola", Dead: "Mocinha", Means: "MOD_RAILGUN", Time: "0:20", Friendly: true},
+				{Killer: "Mocinha", Dead: "Dono da Bola", Means: "MOD_RAILGUN", Time: "0:40"},
+			},
+		},
+	}
+
+	if r := ProcessLines(lines); !reflect.DeepEqual(r, out) {
+		t.Errorf("was expecting %v, but returns %v", out, r)
+	}
+}
@@ -0,0 +1,39 @@
+package parser
+
+// WeaponStats represents the kills and deaths of a player by every means of death
+type WeaponStats struct {
+	Kills  map[string]int `json:"kills,omitempty"`
+	Deaths map[string]int `json:"deaths,omitempty"`
+}
+
+func (g *Game) weapons(player string) *WeaponStats {
+	if g.Weapons == nil {
+		g.Weapons = map[string]*WeaponStats{}
+	}
+	if _, ok := g.Weapons[player]; !ok {
+		g.Weapons[player] = &WeaponStats{}
+	}
+	return g.Weapons[player]
+}
+
+// AddWeaponKill records the means of death of a kill, the kill counts for the killer,
+// unless it is the <world>, a suicide or a teammate, and the death always counts for the dead
+func (g *Game) AddWeaponKill(k *Kill) {
+	if k.Means == "" {
+		return
+	}
+
+	if k.Killer != "<world>" && k.Killer != k.Dead && !k.Friendly {
+		s := g.weapons(k.Killer)
+		if s.Kills == nil {
+			s.Kills = map[string]int{}
+		}
+		s.Kills[k.Means]++
+	}
+
+	s := g.weapons(k.Dead)
+	if s.Deaths == nil {
+		s.Deaths = map[string]int{}
+	}
+	s.Deaths[k.Means]++
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGameAddWeaponKill(t *testing.T) {
+	tt := []struct {
+		description string
+		in          []*Kill
+		out         map[string]*WeaponStats
+	}{
+		{
+			description: "a kill without means",
+			in:          []*Kill{{Killer: "Isgalamido", Dead: "Mocinha"}},
+			out:         nil,
+		},
+		{
+			description: "kills by players, the world and suicides",
+			in: []*Kill{
+				{Killer: "Isgalamido", Dead: "Mocinha", Means: "MOD_RAILGUN"},
+				{Killer: "Isgalamido", Dead: "Mocinha", Means: "MOD_RAILGUN"},
+				{Killer: "<world>", Dead: "Isgalamido", Means: "MOD_TRIGGER_HURT"},
+				{Killer: "Mocinha", Dead: "Mocinha", Means: "MOD_ROCKET_SPLASH"},
+			},
+			out: map[string]*WeaponStats{
+				"Isgalamido": {
+					Kills:  map[string]int{"MOD_RAILGUN": 2},
+					Deaths: map[string]int{"MOD_TRIGGER_HURT": 1},
+				},
+				"Mocinha": {Deaths: map[string]int{"MOD_RAILGUN": 2, "MOD_ROCKET_SPLASH": 1}},
+			},
+		},
+		{
+			description: "a kill of a teammate",
+			in: []*Kill{
+				{Killer: "Isgalamido", Dead: "Mocinha", Means: "MOD_RAILGUN", Friendly: true},
+				{Killer: "Isgalamido", Dead: "Zeh", Means: "MOD_RAILGUN"},
+			},
+			out: map[string]*WeaponStats{
+				"Isgalamido": {Kills: map[string]int{"MOD_RAILGUN": 1}},
+				"Mocinha":    {Deaths: map[string]int{"MOD_RAILGUN": 1}},
+				"Zeh":        {Deaths: map[string]int{"MOD_RAILGUN": 1}},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			g := NewGameEmpty()
+			for _, k := range tc.in {
+				g.AddWeaponKill(k)
+			}
+			if !reflect.DeepEqual(g.Weapons, tc.out) {
+				t.Errorf("was expecting %v, but returns %v", tc.out, g.Weapons)
+			}
+		})
+	}
+}
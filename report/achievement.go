@@ -0,0 +1,160 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// Award types
+const (
+	AwardFirstBlood = "first_blood"
+	AwardStreak     = "streak"
+	AwardMultiKill  = "multi_kill"
+)
+
+// Award represents an achievement of a player in a game, the time is when it started
+type Award struct {
+	Type   string `json:"type"`
+	Player string `json:"player"`
+	Kills  int    `json:"kills"`
+	Time   string `json:"time"`
+}
+
+// AchievementConfig indicates when the kills of a player are awarded
+type AchievementConfig struct {
+	// Streak is how many kills without dying are a streak
+	Streak int
+	// MultiKill is how many kills, each one inside the Window of the previous, are a multi-kill
+	MultiKill int
+	Window    time.Duration
+}
+
+// DefaultAchievementConfig awards the streaks of 5 kills and the multi-kills of 2 kills in 3 seconds
+var DefaultAchievementConfig = AchievementConfig{
+	Streak:    5,
+	MultiKill: 2,
+	Window:    3 * time.Second,
+}
+
+// run is a sequence of kills of a player, without dying
+type run struct {
+	kills int
+	start string
+	// the multi-kill in progress, the last kill time says if the next one is inside the window
+	multi      int
+	multiStart string
+	last       time.Duration
+	timed      bool
+}
+
+// Achievements analyzes the kill sequence of a game, the kills by the <world>, the suicides and
+// the teammate kills are not awarded, but they end the streak and the multi-kill of the dead
+func Achievements(g *parser.Game, c AchievementConfig) []*Award {
+	var awards []*Award
+	runs := map[string]*run{}
+
+	closeMulti := func(player string, r *run) {
+		if c.MultiKill > 0 && r.multi >= c.MultiKill {
+			awards = append(awards, &Award{Type: AwardMultiKill, Player: player, Kills: r.multi, Time: r.multiStart})
+		}
+		r.multi = 0
+	}
+	closeRun := func(player string) {
+		r, ok := runs[player]
+		if !ok {
+			return
+		}
+		closeMulti(player, r)
+		if c.Streak > 0 && r.kills >= c.Streak {
+			awards = append(awards, &Award{Type: AwardStreak, Player: player, Kills: r.kills, Time: r.start})
+		}
+		delete(runs, player)
+	}
+
+	first := true
+	for _, k := range g.KillSequence {
+		if k.Killer != "<world>" && k.Killer != k.Dead && !k.Friendly {
+			if first {
+				awards = append(awards, &Award{Type: AwardFirstBlood, Player: k.Killer, Kills: 1, Time: k.Time})
+				first = false
+			}
+
+			r, ok := runs[k.Killer]
+			if !ok {
+				r = &run{start: k.Time}
+				runs[k.Killer] = r
+			}
+			r.kills++
+
+			t, timed := parser.ParseClock(k.Time)
+			if r.multi > 0 && !(timed && r.timed && t-r.last <= c.Window) {
+				closeMulti(k.Killer, r)
+			}
+			if r.multi == 0 {
+				r.multiStart = k.Time
+			}
+			r.multi++
+			r.last, r.timed = t, timed
+		}
+		closeRun(k.Dead)
+	}
+
+	// the runs still alive end with the game
+	var players []string
+	for p := range runs {
+		players = append(players, p)
+	}
+	sort.Strings(players)
+	for _, p := range players {
+		closeRun(p)
+	}
+
+	// the awards are ordered by when they started
+	sort.SliceStable(awards, func(i, j int) bool {
+		ti, _ := parser.ParseClock(awards[i].Time)
+		tj, _ := parser.ParseClock(awards[j].Time)
+		return ti < tj
+	})
+
+	return awards
+}
+
+var awardNames = map[string]string{
+	AwardFirstBlood: "first blood",
+	AwardStreak:     "streak",
+	AwardMultiKill:  "multi-kill",
+}
+
+// AwardName returns the name of an award type to be displayed
+func AwardName(t string) string {
+	return awardNames[t]
+}
+
+// ForAchievements generates the awards of every game
+func ForAchievements(gs []*parser.Game, c AchievementConfig) string {
+	var games []string
+	for _, g := range gs {
+		awards := Achievements(g, c)
+
+		gameHeader := fmt.Sprintf("Game %s", g.ID)
+		awardsHeader := fmt.Sprintf("Awards: %d", len(awards))
+
+		headerFormat := fmt.Sprintf("%%s%%%ds", 50-len(gameHeader))
+
+		header := fmt.Sprintf(headerFormat, gameHeader, awardsHeader)
+
+		body := `  Time | Award       | Player                         | Kills`
+		for _, a := range awards {
+			namePadLeft := strings.Repeat(" ", int(math.Max(0, float64(30-len(a.Player)))))
+			body += fmt.Sprintf("\n%6s | %-11s | %s%s | %d", a.Time, AwardName(a.Type), a.Player, namePadLeft, a.Kills)
+		}
+
+		games = append(games, fmt.Sprintf("%s\n%s", header, body))
+	}
+	return strings.Join(games, "\n\n")
+}
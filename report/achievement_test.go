@@ -0,0 +1,124 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+var achievementGame = &parser.Game{
+	ID: "1",
+	KillSequence: []*parser.Kill{
+		{Killer: "<world>", Dead: "Zeh", Time: "0:05"},
+		{Killer: "Isgalamido", Dead: "Zeh", Time: "0:10"},
+		{Killer: "Isgalamido", Dead: "Mocinha", Time: "0:11"},
+		{Killer: "Isgalamido", Dead: "Zeh", Time: "0:20"},
+		{Killer: "Mocinha", Dead: "Mocinha", Time: "0:25"},
+		{Killer: "Zeh", Dead: "Isgalamido", Time: "0:30"},
+		{Killer: "Zeh", Dead: "Mocinha", Time: "0:32"},
+		{Killer: "Zeh", Dead: "Isgalamido", Time: "0:34"},
+	},
+}
+
+func TestAwardName(t *testing.T) {
+	tt := []struct {
+		in  string
+		out string
+	}{
+		{AwardFirstBlood, "first blood"},
+		{AwardStreak, "streak"},
+		{AwardMultiKill, "multi-kill"},
+		{"unknown", ""},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.in, func(t *testing.T) {
+			if r := AwardName(tc.in); r != tc.out {
+				t.Errorf("was expecting %v, but returns %v", tc.out, r)
+			}
+		})
+	}
+}
+
+func TestAchievements(t *testing.T) {
+	tt := []struct {
+		description string
+		config      AchievementConfig
+		in          *parser.Game
+		out         []*Award
+	}{
+		{
+			description: "without kills",
+			config:      DefaultAchievementConfig,
+			in:          &parser.Game{ID: "1"},
+			out:         nil,
+		},
+		{
+			description: "with the default config",
+			config:      DefaultAchievementConfig,
+			in:          achievementGame,
+			out: []*Award{
+				{Type: AwardFirstBlood, Player: "Isgalamido", Kills: 1, Time: "0:10"},
+				{Type: AwardMultiKill, Player: "Isgalamido", Kills: 2, Time: "0:10"},
+				{Type: AwardMultiKill, Player: "Zeh", Kills: 3, Time: "0:30"},
+			},
+		},
+		{
+			description: "with short streaks and a short window",
+			config:      AchievementConfig{Streak: 3, MultiKill: 2, Window: time.Second},
+			in:          achievementGame,
+			out: []*Award{
+				{Type: AwardFirstBlood, Player: "Isgalamido", Kills: 1, Time: "0:10"},
+				{Type: AwardMultiKill, Player: "Isgalamido", Kills: 2, Time: "0:10"},
+				{Type: AwardStreak, Player: "Isgalamido", Kills: 3, Time: "0:10"},
+				{Type: AwardStreak, Player: "Zeh", Kills: 3, Time: "0:30"},
+			},
+		},
+		{
+			description: "with teammate kills",
+			config:      AchievementConfig{Streak: 2, MultiKill: 2, Window: time.Second},
+			in: &parser.Game{
+				ID:       "1",
+				GameType: parser.GameTypeTDM,
+				KillSequence: []*parser.Kill{
+					{Killer: "Isgalamido", Dead: "Dono da Bola", Time: "0:10", Friendly: true},
+					{Killer: "Isgalamido", Dead: "Zeh", Time: "0:11"},
+					{Killer: "Mocinha", Dead: "Isgalamido", Time: "0:20"},
+					{Killer: "Mocinha", Dead: "Dono da Bola", Time: "0:30"},
+					{Killer: "Zeh", Dead: "Mocinha", Time: "0:40", Friendly: true},
+					{Killer: "Mocinha", Dead: "Isgalamido", Time: "0:50"},
+				},
+			},
+			out: []*Award{
+				{Type: AwardFirstBlood, Player: "Isgalamido", Kills: 1, Time: "0:11"},
+				{Type: AwardStreak, Player: "Mocinha", Kills: 2, Time: "0:20"},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			if r := Achievements(tc.in, tc.config); !reflect.DeepEqual(r, tc.out) {
+				t.Errorf("was expecting %v, but returns %v", tc.out, r)
+			}
+		})
+	}
+}
+
+func TestForAchievements(t *testing.T) {
+	out := `Game 1                                   Awards: 3
+  Time | Award       | Player                         | Kills
+  0:10 | first blood | Isgalamido                     | 1
+  0:10 | multi-kill  | Isgalamido                     | 2
+  0:30 | multi-kill  | Zeh                            | 3
+
+Game 2                                   Awards: 0
+  Time | Award       | Player                         | Kills`
+
+	gs := []*parser.Game{achievementGame, {ID: "2"}}
+	if r := ForAchievements(gs, DefaultAchievementConfig); r != out {
+		t.Errorf("was expecting\n%v\nbut returns\n%v", out, r)
+	}
+}
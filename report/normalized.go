@@ -0,0 +1,188 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// the ranking modes, how the points of the players are compared
+const (
+	ModeTotal     = "total"
+	ModeAverage   = "average"
+	ModePerMinute = "per_minute"
+)
+
+var modeDescriptions = map[string]string{
+	ModeTotal:     "total points",
+	ModeAverage:   "average points per game",
+	ModePerMinute: "points per minute played",
+}
+
+// RankingConfig represents how the players are ranked, by their total points, their average
+// points per game or their points per minute played, how many games they need to be ranked
+// and if the flag actions of the capture the flag games score besides the kills
+type RankingConfig struct {
+	Mode     string
+	MinGames int
+	CTFBonus bool
+}
+
+// DefaultRankingConfig ranks every player by the total points
+var DefaultRankingConfig = RankingConfig{
+	Mode:     ModeTotal,
+	MinGames: 0,
+}
+
+// Validate verifies if the mode is known and the minimum games is not negative
+func (c RankingConfig) Validate() error {
+	if _, ok := modeDescriptions[c.Mode]; !ok {
+		return fmt.Errorf("unknown ranking mode %q, should be total, average or per_minute", c.Mode)
+	}
+	if c.MinGames < 0 {
+		return fmt.Errorf("invalid minimum games %d", c.MinGames)
+	}
+	return nil
+}
+
+// Description explains the config, to be shown with the ranking
+func (c RankingConfig) Description() string {
+	d := modeDescriptions[c.Mode]
+	if c.CTFBonus {
+		d += ", with the flag bonuses"
+	}
+	if c.MinGames > 0 {
+		d += fmt.Sprintf(", at least %d games", c.MinGames)
+	}
+	return d
+}
+
+// Score represents the points of a player, the games played and the minutes played,
+// and the score compared in the ranking, which depends on the ranking mode
+type Score struct {
+	Name    string  `json:"name"`
+	Points  int     `json:"points"`
+	Games   int     `json:"games"`
+	Minutes float64 `json:"minutes"`
+	Score   float64 `json:"score"`
+}
+
+// NewScore creates a new Score instance
+func NewScore(name string) *Score {
+	return &Score{
+		Name: name,
+	}
+}
+
+// NormalizedRanking accumulates the player points, games and minutes played, to rank them by the config
+type NormalizedRanking struct {
+	Config     RankingConfig
+	TotalKills int
+	Players    map[string]*Score
+}
+
+// NewNormalizedRanking creates a new NormalizedRanking instance
+func NewNormalizedRanking(c RankingConfig) *NormalizedRanking {
+	return &NormalizedRanking{
+		Config:     c,
+		TotalKills: 0,
+		Players:    map[string]*Score{},
+	}
+}
+
+// AddGame integrate the game points, like the general ranking, and the minutes played to the ranking
+func (r *NormalizedRanking) AddGame(g *parser.Game) {
+	game := NewRanking()
+	game.CTFBonus = r.Config.CTFBonus
+	game.AddGame(g)
+	r.TotalKills += game.TotalKills
+
+	for name, p := range game.Players {
+		if _, ok := r.Players[name]; !ok {
+			r.Players[name] = NewScore(name)
+		}
+		s := r.Players[name]
+		s.Points += p.Points
+		s.Games++
+		s.Minutes += float64(g.PlayTime[name]) / 60
+	}
+}
+
+func (r *NormalizedRanking) score(s *Score) float64 {
+	switch r.Config.Mode {
+	case ModeAverage:
+		return float64(s.Points) / float64(s.Games)
+	case ModePerMinute:
+		if s.Minutes == 0 {
+			return 0
+		}
+		return float64(s.Points) / s.Minutes
+	}
+	return float64(s.Points)
+}
+
+// eligible verifies if the player played the minimum games, and some time when ranked per minute
+func (r *NormalizedRanking) eligible(s *Score) bool {
+	if s.Games < r.Config.MinGames {
+		return false
+	}
+	return r.Config.Mode != ModePerMinute || s.Minutes > 0
+}
+
+// Ordered returns the eligible players ordered by score, which is computed by the config
+func (r *NormalizedRanking) Ordered() []*Score {
+	var ss []*Score
+	for _, s := range r.Players {
+		if r.eligible(s) {
+			s.Score = r.score(s)
+			ss = append(ss, s)
+		}
+	}
+
+	sort.Slice(ss, func(i, j int) bool {
+		return ss[i].Score > ss[j].Score || (ss[i].Score == ss[j].Score && ss[i].Name < ss[j].Name)
+	})
+
+	return ss
+}
+
+// Report generates a text for the ranking
+func (r *NormalizedRanking) Report() string {
+	header := `Position | Player                         | Score    | Games`
+	body := ""
+	for i, s := range r.Ordered() {
+		namePadLeft := strings.Repeat(" ", int(math.Max(0, float64(30-len(s.Name)))))
+		body += fmt.Sprintf("%8d | %s%s | %8.2f | %d\n", i+1, s.Name, namePadLeft, s.Score, s.Games)
+	}
+	body = strings.TrimRight(body, "\n")
+	return fmt.Sprintf("%s\n%s", header, body)
+}
+
+// ForRanking generates a ranking for many games, ranked by the config, which is described in the header
+func ForRanking(gs []*parser.Game, c RankingConfig) string {
+	r := NewNormalizedRanking(c)
+	for _, g := range gs {
+		r.AddGame(g)
+	}
+
+	rankingHeader := "General Ranking"
+	totalKillsHeader := fmt.Sprintf("Total Kills: %d", r.TotalKills)
+
+	headerFormat := fmt.Sprintf("%%s%%%ds", 50-len(rankingHeader))
+
+	header := fmt.Sprintf(headerFormat, rankingHeader, totalKillsHeader)
+
+	return fmt.Sprintf("%s\nRanked by %s\n%s", header, c.Description(), r.Report())
+}
+
+// ForGeneral generates the general report, the original points layout of ForGames for the default
+// config and the ranking of ForRanking when the config asks for other measures
+func ForGeneral(gs []*parser.Game, c RankingConfig) string {
+	if c == DefaultRankingConfig {
+		return ForGames(gs)
+	}
+	return ForRanking(gs, c)
+}
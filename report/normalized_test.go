@@ -0,0 +1,178 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+var normalizedGames = []*parser.Game{
+	{
+		ID:         "1",
+		TotalKills: 6,
+		Players:    []string{"player one", "player two"},
+		Kills:      map[string]int{"player one": 4, "player two": 2},
+		PlayTime:   map[string]int{"player one": 120, "player two": 60},
+	},
+	{
+		ID:         "2",
+		GameType:   parser.GameTypeCTF,
+		TotalKills: 3,
+		Players:    []string{"player one", "player three"},
+		Kills:      map[string]int{"player one": 2, "player three": 1},
+		PlayTime:   map[string]int{"player one": 60},
+		CTF:        map[string]*parser.CTFStats{"player three": {Pickups: 1, Captures: 1}},
+	},
+}
+
+func TestRankingConfigValidate(t *testing.T) {
+	tt := []struct {
+		description string
+		in          RankingConfig
+		err         bool
+	}{
+		{description: "default", in: DefaultRankingConfig},
+		{description: "average", in: RankingConfig{Mode: ModeAverage, MinGames: 3}},
+		{description: "per minute", in: RankingConfig{Mode: ModePerMinute}},
+		{description: "unknown mode", in: RankingConfig{Mode: "best"}, err: true},
+		{description: "negative minimum games", in: RankingConfig{Mode: ModeTotal, MinGames: -1}, err: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			if err := tc.in.Validate(); (err != nil) != tc.err {
+				t.Errorf("was expecting error %v, but returns %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestNormalizedRankingOrdered(t *testing.T) {
+	tt := []struct {
+		description string
+		in          RankingConfig
+		out         []*Score
+	}{
+		{
+			description: "total",
+			in:          DefaultRankingConfig,
+			out: []*Score{
+				{Name: "player one", Points: 6, Games: 2, Minutes: 3, Score: 6},
+				{Name: "player two", Points: 2, Games: 1, Minutes: 1, Score: 2},
+				{Name: "player three", Points: 1, Games: 1, Minutes: 0, Score: 1},
+			},
+		},
+		{
+			description: "total with the flag bonuses",
+			in:          RankingConfig{Mode: ModeTotal, CTFBonus: true},
+			out: []*Score{
+				{Name: "player one", Points: 6, Games: 2, Minutes: 3, Score: 6},
+				{Name: "player three", Points: 6, Games: 1, Minutes: 0, Score: 6},
+				{Name: "player two", Points: 2, Games: 1, Minutes: 1, Score: 2},
+			},
+		},
+		{
+			description: "average",
+			in:          RankingConfig{Mode: ModeAverage},
+			out: []*Score{
+				{Name: "player one", Points: 6, Games: 2, Minutes: 3, Score: 3},
+				{Name: "player two", Points: 2, Games: 1, Minutes: 1, Score: 2},
+				{Name: "player three", Points: 1, Games: 1, Minutes: 0, Score: 1},
+			},
+		},
+		{
+			description: "per minute, without the players without play time",
+			in:          RankingConfig{Mode: ModePerMinute},
+			out: []*Score{
+				{Name: "player one", Points: 6, Games: 2, Minutes: 3, Score: 2},
+				{Name: "player two", Points: 2, Games: 1, Minutes: 1, Score: 2},
+			},
+		},
+		{
+			description: "minimum games",
+			in:          RankingConfig{Mode: ModeAverage, MinGames: 2},
+			out: []*Score{
+				{Name: "player one", Points: 6, Games: 2, Minutes: 3, Score: 3},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			r := NewNormalizedRanking(tc.in)
+			for _, g := range normalizedGames {
+				r.AddGame(g)
+			}
+
+			if r.TotalKills != 9 {
+				t.Errorf("was expecting 9 total kills, but returns %d", r.TotalKills)
+			}
+			if out := r.Ordered(); !reflect.DeepEqual(out, tc.out) {
+				t.Errorf("was expecting %+v, but returns %+v", tc.out, out)
+			}
+		})
+	}
+}
+
+func TestForRanking(t *testing.T) {
+	tt := []struct {
+		description string
+		in          RankingConfig
+		out         string
+	}{
+		{
+			description: "by the total points",
+			in:          DefaultRankingConfig,
+			out: `General Ranking                     Total Kills: 9
+Ranked by total points
+Position | Player                         | Score    | Games
+       1 | player one                     |     6.00 | 2
+       2 | player two                     |     2.00 | 1
+       3 | player three                   |     1.00 | 1`,
+		},
+		{
+			description: "by the average points with minimum games",
+			in:          RankingConfig{Mode: ModeAverage, MinGames: 2},
+			out: `General Ranking                     Total Kills: 9
+Ranked by average points per game, at least 2 games
+Position | Player                         | Score    | Games
+       1 | player one                     |     3.00 | 2`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			if r := ForRanking(normalizedGames, tc.in); r != tc.out {
+				t.Errorf("was expecting\n%v\nbut returns\n%v", tc.out, r)
+			}
+		})
+	}
+}
+
+func TestForGeneral(t *testing.T) {
+	tt := []struct {
+		description string
+		in          RankingConfig
+		out         string
+	}{
+		{
+			description: "the points with the default config",
+			in:          DefaultRankingConfig,
+			out:         ForGames(normalizedGames),
+		},
+		{
+			description: "the ranking with other config",
+			in:          RankingConfig{Mode: ModeAverage, MinGames: 2},
+			out:         ForRanking(normalizedGames, RankingConfig{Mode: ModeAverage, MinGames: 2}),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			if r := ForGeneral(normalizedGames, tc.in); r != tc.out {
+				t.Errorf("was expecting\n%v\nbut returns\n%v", tc.out, r)
+			}
+		})
+	}
+}
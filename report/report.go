@@ -23,10 +23,12 @@ func NewPlayer(name string, points int) *Player {
 	}
 }
 
-// Ranking accumulates the player points
+// Ranking accumulates the player points, the kills by default,
+// with CTFBonus the flag actions of the capture the flag games score too
 type Ranking struct {
 	TotalKills int
 	Players    map[string]*Player
+	CTFBonus   bool
 }
 
 // NewRanking creates a new Ranking instance
@@ -37,17 +39,33 @@ func NewRanking() *Ranking {
 	}
 }
 
-// AddGame integrate game points to the ranking
+// CTF bonuses, the points the game gives for the flag actions
+const (
+	CaptureBonus     = 5
+	ReturnBonus      = 1
+	CarrierKillBonus = 2
+)
+
+// AddGame integrate game points to the ranking, with the flag bonuses in capture the flag games when enabled
 func (r *Ranking) AddGame(g *parser.Game) {
 	r.TotalKills += g.TotalKills
 	for p, k := range g.Kills {
-		if _, ok := r.Players[p]; !ok {
-			r.Players[p] = NewPlayer(p, 0)
+		r.addPoints(p, k)
+	}
+	if r.CTFBonus && g.GameType == parser.GameTypeCTF {
+		for p, s := range g.CTF {
+			r.addPoints(p, s.Captures*CaptureBonus+s.Returns*ReturnBonus+s.CarrierKills*CarrierKillBonus)
 		}
-		r.Players[p].Points += k
 	}
 }
 
+func (r *Ranking) addPoints(player string, points int) {
+	if _, ok := r.Players[player]; !ok {
+		r.Players[player] = NewPlayer(player, 0)
+	}
+	r.Players[player].Points += points
+}
+
 // Ordered returns the players ordered by points
 func (r *Ranking) Ordered() []*Player {
 	var p []*Player
@@ -1,15 +1,47 @@
 package report
 
 import (
+	"io/ioutil"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/bgildson/enext-challenge/parser"
 )
 
 func TestRankingAddGame(t *testing.T) {
+	ctfGames := []*parser.Game{
+		{
+			ID:         "1",
+			GameType:   parser.GameTypeCTF,
+			TotalKills: 3,
+			Players:    []string{"player one", "player two"},
+			Kills: map[string]int{
+				"player one": 2,
+				"player two": 1,
+			},
+			CTF: map[string]*parser.CTFStats{
+				"player one":   {Pickups: 2, Captures: 1},
+				"player two":   {Returns: 1, CarrierKills: 1},
+				"player three": {Returns: 2},
+			},
+		},
+		{
+			ID:         "2",
+			TotalKills: 1,
+			Players:    []string{"player one"},
+			Kills: map[string]int{
+				"player one": 1,
+			},
+			CTF: map[string]*parser.CTFStats{
+				"player one": {Captures: 1},
+			},
+		},
+	}
+
 	tt := []struct {
 		description string
+		ctfBonus    bool
 		in          []*parser.Game
 		out         *Ranking
 	}{
@@ -65,11 +97,37 @@ func TestRankingAddGame(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "only the kills of a ctf game",
+			in:          ctfGames,
+			out: &Ranking{
+				TotalKills: 4,
+				Players: map[string]*Player{
+					"player one": NewPlayer("player one", 3),
+					"player two": NewPlayer("player two", 1),
+				},
+			},
+		},
+		{
+			description: "with the flag bonuses of a ctf game",
+			ctfBonus:    true,
+			in:          ctfGames,
+			out: &Ranking{
+				TotalKills: 4,
+				Players: map[string]*Player{
+					"player one":   NewPlayer("player one", 8),
+					"player two":   NewPlayer("player two", 4),
+					"player three": NewPlayer("player three", 2),
+				},
+				CTFBonus: true,
+			},
+		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.description, func(t *testing.T) {
 			r := NewRanking()
+			r.CTFBonus = tc.ctfBonus
 			for _, g := range tc.in {
 				r.AddGame(g)
 			}
@@ -267,3 +325,33 @@ Position | Player                         | Points
 		})
 	}
 }
+
+// TestForGamesChallengeLog keeps the general ranking of the challenge log, only the kills score by default
+func TestForGamesChallengeLog(t *testing.T) {
+	b, err := ioutil.ReadFile("../games.log")
+	if err != nil {
+		t.Fatalf("could not read the games log: %v", err)
+	}
+
+	out := `General Ranking                  Total Kills: 1069
+Position | Player                         | Points
+       1 | Isgalamido                     | 138
+       2 | Zeh                            | 120
+       3 | Oootsimo                       | 108
+       4 | Assasinu Credi                 | 91
+       5 | Dono da Bola                   | 48
+       6 | Chessus                        | 32
+       7 | Maluquinho                     | 0
+       8 | Mocinha                        | 0
+       9 | UnnamedPlayer                  | 0
+      10 | Mal                            | -9`
+
+	games := parser.ProcessLines(strings.Split(string(b), "\n"))
+	if r := ForGames(games); r != out {
+		t.Errorf("was expecting\n%v\nbut returns\n%v", out, r)
+	}
+	// the general report keeps the original layout with the default ranking
+	if r := ForGeneral(games, DefaultRankingConfig); r != out {
+		t.Errorf("was expecting\n%v\nbut returns\n%v", out, r)
+	}
+}
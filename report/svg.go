@@ -0,0 +1,163 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// chart dimensions, in pixels
+const (
+	chartWidth   = 640
+	chartPadding = 16
+	titleHeight  = 40
+	barHeight    = 20
+	barGap       = 8
+	labelWidth   = 200
+	lineHeight   = 360
+	legendWidth  = 160
+)
+
+// palette colors the players of the line chart, repeating when there are more players
+var palette = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac"}
+
+func svgOpen(b *strings.Builder, width, height int) {
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`, width, height, width, height)
+	fmt.Fprintf(b, "\n"+`<rect width="%d" height="%d" fill="#ffffff"/>`, width, height)
+}
+
+func svgText(b *strings.Builder, x, y float64, anchor, text string) {
+	fmt.Fprintf(b, "\n"+`<text x="%.1f" y="%.1f" text-anchor="%s">%s</text>`, x, y, anchor, html.EscapeString(text))
+}
+
+func svgTitle(b *strings.Builder, width int, title, subtitle string) {
+	fmt.Fprintf(b, "\n"+`<text x="%d" y="%d" font-size="16" font-weight="bold">%s</text>`, chartPadding, 26, html.EscapeString(title))
+	fmt.Fprintf(b, "\n"+`<text x="%d" y="%d" text-anchor="end">%s</text>`, width-chartPadding, 26, html.EscapeString(subtitle))
+}
+
+// span returns the range of the values, always including the zero and never empty
+func span(values []int) (min, max int) {
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	if min == max {
+		max++
+	}
+	return min, max
+}
+
+// RankingSVG draws the ranking as a horizontal bar chart, the negative points go left of the zero line
+func RankingSVG(r *Ranking) string {
+	players := r.Ordered()
+	height := titleHeight + len(players)*(barHeight+barGap) + chartPadding
+
+	var points []int
+	for _, p := range players {
+		points = append(points, p.Points)
+	}
+	min, max := span(points)
+
+	// the bars area starts after the names and leaves room for the points after the bars
+	left := float64(labelWidth)
+	scale := float64(chartWidth-labelWidth-2*chartPadding-40) / float64(max-min)
+	zero := left - float64(min)*scale
+
+	var b strings.Builder
+	svgOpen(&b, chartWidth, height)
+	svgTitle(&b, chartWidth, "General Ranking", fmt.Sprintf("Total Kills: %d", r.TotalKills))
+
+	for i, p := range players {
+		y := float64(titleHeight + i*(barHeight+barGap))
+		x, width := zero, float64(p.Points)*scale
+		if width < 0 {
+			x, width = zero+width, -width
+		}
+		svgText(&b, left-8, y+barHeight-6, "end", p.Name)
+		fmt.Fprintf(&b, "\n"+`<rect x="%.1f" y="%.1f" width="%.1f" height="%d" fill="%s"/>`, x, y, width, barHeight, palette[0])
+		svgText(&b, x+width+4, y+barHeight-6, "start", fmt.Sprint(p.Points))
+	}
+	fmt.Fprintf(&b, "\n"+`<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="#333333"/>`, zero, titleHeight-4, zero, height-chartPadding)
+
+	b.WriteString("\n</svg>\n")
+	return b.String()
+}
+
+// TimelineSVG draws the score of every player over the game clock as a line chart, from the kill timeline
+func TimelineSVG(g *parser.Game) string {
+	players := TimelinePlayers(g)
+	timeline := Timeline(g)
+
+	// the kills without a valid clock are placed at the previous kill time
+	var times []int
+	var scores []int
+	for _, p := range timeline {
+		t, ok := parser.ParseClock(p.Time)
+		seconds := int(t.Seconds())
+		if !ok && len(times) > 0 {
+			seconds = times[len(times)-1]
+		}
+		times = append(times, seconds)
+		for _, s := range p.Scores {
+			scores = append(scores, s)
+		}
+	}
+	min, max := span(scores)
+
+	first, last := 0, 1
+	if len(times) > 0 {
+		first, last = times[0], times[len(times)-1]
+		if last <= first {
+			last = first + 1
+		}
+	}
+
+	// the plot area, with the legend on the right
+	left, right := float64(chartPadding+40), float64(chartWidth-legendWidth)
+	top, bottom := float64(titleHeight), float64(lineHeight-chartPadding-20)
+	x := func(seconds int) float64 {
+		return left + float64(seconds-first)*(right-left)/float64(last-first)
+	}
+	y := func(score int) float64 {
+		return bottom - float64(score-min)*(bottom-top)/float64(max-min)
+	}
+
+	var b strings.Builder
+	svgOpen(&b, chartWidth, lineHeight)
+	svgTitle(&b, chartWidth, fmt.Sprintf("Game %s", g.ID), fmt.Sprintf("Kills: %d", len(timeline)))
+
+	// the axes, with the zero line and the first and last kill times
+	fmt.Fprintf(&b, "\n"+`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#333333"/>`, left, top, left, bottom)
+	fmt.Fprintf(&b, "\n"+`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#cccccc"/>`, left, y(0), right, y(0))
+	for _, s := range []int{min, 0, max} {
+		svgText(&b, left-6, y(s)+4, "end", fmt.Sprint(s))
+	}
+	if len(timeline) > 0 {
+		svgText(&b, left, bottom+16, "start", timeline[0].Time)
+		svgText(&b, right, bottom+16, "end", timeline[len(timeline)-1].Time)
+	}
+
+	for i, name := range players {
+		color := palette[i%len(palette)]
+
+		// every player starts with zero points, before the first kill
+		path := []string{fmt.Sprintf("%.1f,%.1f", x(first), y(0))}
+		for j, p := range timeline {
+			path = append(path, fmt.Sprintf("%.1f,%.1f", x(times[j]), y(p.Scores[name])))
+		}
+		fmt.Fprintf(&b, "\n"+`<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`, strings.Join(path, " "), color)
+
+		ly := top + float64(i*(barHeight))
+		fmt.Fprintf(&b, "\n"+`<rect x="%.1f" y="%.1f" width="12" height="12" fill="%s"/>`, right+16, ly, color)
+		svgText(&b, right+34, ly+10, "start", name)
+	}
+
+	b.WriteString("\n</svg>\n")
+	return b.String()
+}
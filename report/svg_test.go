@@ -0,0 +1,100 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// elements decodes the svg, failing when it is not well formed, and counts the elements by name
+func elements(t *testing.T, svg string) map[string]int {
+	t.Helper()
+
+	count := map[string]int{}
+	d := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return count
+		}
+		if err != nil {
+			t.Fatalf("was expecting a well formed svg, but returns %v", err)
+		}
+		if s, ok := tok.(xml.StartElement); ok {
+			count[s.Name.Local]++
+		}
+	}
+}
+
+func TestSpan(t *testing.T) {
+	tt := []struct {
+		description string
+		in          []int
+		min, max    int
+	}{
+		{"without values", nil, 0, 1},
+		{"positive values", []int{3, 5}, 0, 5},
+		{"negative values", []int{-3, 2}, -3, 2},
+		{"only zeros", []int{0, 0}, 0, 1},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			if min, max := span(tc.in); min != tc.min || max != tc.max {
+				t.Errorf("was expecting %d %d, but returns %d %d", tc.min, tc.max, min, max)
+			}
+		})
+	}
+}
+
+func TestRankingSVG(t *testing.T) {
+	r := NewRanking()
+	r.TotalKills = 7
+	r.Players = map[string]*Player{
+		"player one":   NewPlayer("player one", 5),
+		"player <two>": NewPlayer("player <two>", -2),
+	}
+
+	svg := RankingSVG(r)
+
+	// a background, and a bar for every player
+	if count := elements(t, svg); count["rect"] != 3 || count["line"] != 1 {
+		t.Errorf("was expecting 3 rects and 1 line, but returns %v", count)
+	}
+	for _, text := range []string{"General Ranking", "Total Kills: 7", "player one", "player &lt;two&gt;", ">-2<"} {
+		if !strings.Contains(svg, text) {
+			t.Errorf("was expecting %q in the svg, but returns\n%s", text, svg)
+		}
+	}
+
+	// the zero line is after the negative bar, which ends on it
+	if !strings.Contains(svg, `<line x1="305.1"`) || !strings.Contains(svg, `<rect x="200.0" y="68.0" width="105.1"`) {
+		t.Errorf("was expecting the negative bar before the zero line, but returns\n%s", svg)
+	}
+}
+
+func TestTimelineSVG(t *testing.T) {
+	svg := TimelineSVG(timelineGame)
+
+	// a line for every player, with the starting point and a point for every kill
+	count := elements(t, svg)
+	if count["polyline"] != 3 {
+		t.Errorf("was expecting 3 polylines, but returns %v", count)
+	}
+	if n := strings.Count(svg, ","); n != 3*5 {
+		t.Errorf("was expecting 15 points, but returns %d", n)
+	}
+	for _, text := range []string{"Game 1", "Kills: 4", "Isgalamido", "Mocinha", "Zeh", ">0:05<", ">0:20<"} {
+		if !strings.Contains(svg, text) {
+			t.Errorf("was expecting %q in the svg, but returns\n%s", text, svg)
+		}
+	}
+
+	// a game without kills has only the starting points
+	if count := elements(t, TimelineSVG(&parser.Game{ID: "2", Players: []string{"Zeh"}})); count["polyline"] != 1 {
+		t.Errorf("was expecting 1 polyline, but returns %v", count)
+	}
+}
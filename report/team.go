@@ -0,0 +1,137 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// Team represents the results of a team in the team games
+type Team struct {
+	Name         string `json:"name"`
+	Wins         int    `json:"wins"`
+	Score        int    `json:"score"`
+	Kills        int    `json:"kills"`
+	FriendlyFire int    `json:"friendly_fire"`
+}
+
+// NewTeam creates a new Team instance
+func NewTeam(name string) *Team {
+	return &Team{
+		Name: name,
+	}
+}
+
+// TeamRanking accumulates the team results, only the team games are considered
+type TeamRanking struct {
+	Games int
+	Teams map[string]*Team
+}
+
+// NewTeamRanking creates a new TeamRanking instance
+func NewTeamRanking() *TeamRanking {
+	return &TeamRanking{
+		Games: 0,
+		Teams: map[string]*Team{},
+	}
+}
+
+func (r *TeamRanking) team(name string) *Team {
+	if _, ok := r.Teams[name]; !ok {
+		r.Teams[name] = NewTeam(name)
+	}
+	return r.Teams[name]
+}
+
+// AddGame integrate the team results of a game to the ranking, the score is the
+// one reported by the server, or the team kills when the game ended without it
+func (r *TeamRanking) AddGame(g *parser.Game) {
+	if !g.IsTeamGame() {
+		return
+	}
+	r.Games++
+
+	scores := g.TeamScores
+	if len(scores) == 0 {
+		scores = g.TeamKills
+	}
+
+	// the friendly fire is attributed to the last team of the player
+	teams := map[string]string{}
+	for _, c := range g.TeamChanges {
+		teams[c.Player] = c.Team
+	}
+
+	for _, name := range []string{parser.TeamRed, parser.TeamBlue} {
+		t := r.team(name)
+		t.Score += scores[name]
+		t.Kills += g.TeamKills[name]
+	}
+	for p, n := range g.FriendlyFire {
+		if team, ok := teams[p]; ok {
+			r.team(team).FriendlyFire += n
+		}
+	}
+
+	// ties have no winner
+	red, blue := scores[parser.TeamRed], scores[parser.TeamBlue]
+	if red > blue {
+		r.team(parser.TeamRed).Wins++
+	} else if blue > red {
+		r.team(parser.TeamBlue).Wins++
+	}
+}
+
+// Ordered returns the teams ordered by wins, then by score
+func (r *TeamRanking) Ordered() []*Team {
+	var ts []*Team
+	for _, t := range r.Teams {
+		ts = append(ts, t)
+	}
+
+	sort.Slice(ts, func(i, j int) bool {
+		if ts[i].Wins != ts[j].Wins {
+			return ts[i].Wins > ts[j].Wins
+		}
+		if ts[i].Score != ts[j].Score {
+			return ts[i].Score > ts[j].Score
+		}
+		return ts[i].Name < ts[j].Name
+	})
+
+	return ts
+}
+
+// Report generates a text for the team ranking
+func (r *TeamRanking) Report() string {
+	header := `Position | Team       | Wins | Score | Kills | Friendly Fire`
+	body := ""
+	for i, t := range r.Ordered() {
+		namePadLeft := strings.Repeat(" ", int(math.Max(0, float64(10-len(t.Name)))))
+		body += fmt.Sprintf("%8d | %s%s | %4d | %5d | %5d | %d\n", i+1, t.Name, namePadLeft, t.Wins, t.Score, t.Kills, t.FriendlyFire)
+	}
+	body = strings.TrimRight(body, "\n")
+	return fmt.Sprintf("%s\n%s", header, body)
+}
+
+// ForTeams generates a team ranking for the team games
+func ForTeams(gs []*parser.Game) string {
+	r := NewTeamRanking()
+	for _, g := range gs {
+		r.AddGame(g)
+	}
+
+	gameHeader := fmt.Sprintf("Team Ranking")
+	gamesHeader := fmt.Sprintf("Team Games: %d", r.Games)
+
+	headerFormat := fmt.Sprintf("%%s%%%ds", 50-len(gameHeader))
+
+	header := fmt.Sprintf(headerFormat, gameHeader, gamesHeader)
+
+	body := r.Report()
+
+	return fmt.Sprintf("%s\n%s", header, body)
+}
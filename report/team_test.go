@@ -0,0 +1,69 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+var teamGames = []*parser.Game{
+	{
+		ID:         "1",
+		GameType:   parser.GameTypeCTF,
+		TotalKills: 4,
+		Players:    []string{"player one", "player two", "player three"},
+		Kills:      map[string]int{"player one": 2, "player two": 0, "player three": -1},
+		TeamChanges: []*parser.TeamChange{
+			{Player: "player one", Team: parser.TeamRed, Time: "0:01"},
+			{Player: "player two", Team: parser.TeamBlue, Time: "0:01"},
+			{Player: "player three", Team: parser.TeamBlue, Time: "0:01"},
+		},
+		TeamKills:    map[string]int{parser.TeamRed: 2, parser.TeamBlue: 1},
+		TeamScores:   map[string]int{parser.TeamRed: 3, parser.TeamBlue: 5},
+		FriendlyFire: map[string]int{"player three": 1},
+	},
+	{
+		ID:         "2",
+		GameType:   parser.GameTypeTDM,
+		TotalKills: 3,
+		Players:    []string{"player one", "player two"},
+		Kills:      map[string]int{"player one": 3, "player two": 0},
+		TeamKills:  map[string]int{parser.TeamRed: 3},
+	},
+	{
+		ID:         "3",
+		TotalKills: 10,
+		Players:    []string{"player one"},
+		Kills:      map[string]int{"player one": -10},
+	},
+}
+
+func TestTeamRankingAddGame(t *testing.T) {
+	r := NewTeamRanking()
+	for _, g := range teamGames {
+		r.AddGame(g)
+	}
+
+	out := &TeamRanking{
+		Games: 2,
+		Teams: map[string]*Team{
+			parser.TeamRed:  {Name: parser.TeamRed, Wins: 1, Score: 6, Kills: 5},
+			parser.TeamBlue: {Name: parser.TeamBlue, Wins: 1, Score: 5, Kills: 1, FriendlyFire: 1},
+		},
+	}
+	if !reflect.DeepEqual(r, out) {
+		t.Errorf("was expecting %v, but returns %v", out, r)
+	}
+}
+
+func TestForTeams(t *testing.T) {
+	out := `Team Ranking                         Team Games: 2
+Position | Team       | Wins | Score | Kills | Friendly Fire
+       1 | red        |    1 |     6 |     5 | 0
+       2 | blue       |    1 |     5 |     1 | 1`
+
+	if r := ForTeams(teamGames); r != out {
+		t.Errorf("was expecting\n%v\nbut returns\n%v", out, r)
+	}
+}
@@ -0,0 +1,138 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// TimelinePoint represents a kill of the game and the score of every player right after it
+type TimelinePoint struct {
+	Time   string         `json:"time"`
+	Killer string         `json:"killer"`
+	Dead   string         `json:"dead"`
+	Means  string         `json:"means,omitempty"`
+	Scores map[string]int `json:"scores"`
+}
+
+// Timeline replays the kill sequence of a game, scoring the kills like the game,
+// so the scores of the last point are the game kills
+func Timeline(g *parser.Game) []*TimelinePoint {
+	scores := map[string]int{}
+	for _, p := range TimelinePlayers(g) {
+		scores[p] = 0
+	}
+
+	var points []*TimelinePoint
+	for _, k := range g.KillSequence {
+		switch {
+		case k.Friendly:
+			scores[k.Killer]--
+		case k.Killer == "<world>":
+			scores[k.Dead]--
+		case k.Killer != k.Dead:
+			scores[k.Killer]++
+		}
+
+		p := &TimelinePoint{
+			Time:   k.Time,
+			Killer: k.Killer,
+			Dead:   k.Dead,
+			Means:  k.Means,
+			Scores: map[string]int{},
+		}
+		for name, score := range scores {
+			p.Scores[name] = score
+		}
+		points = append(points, p)
+	}
+
+	return points
+}
+
+// TimelinePlayers returns the players of the game, followed by the ones only found in the kill sequence
+func TimelinePlayers(g *parser.Game) []string {
+	players := append([]string{}, g.Players...)
+	known := map[string]bool{}
+	for _, p := range players {
+		known[p] = true
+	}
+	for _, k := range g.KillSequence {
+		for _, p := range []string{k.Killer, k.Dead} {
+			if p != "<world>" && !known[p] {
+				known[p] = true
+				players = append(players, p)
+			}
+		}
+	}
+	return players
+}
+
+// ForTimeline generates a text for the timeline of a game, with a column for the score of every player
+func ForTimeline(g *parser.Game) string {
+	players := TimelinePlayers(g)
+
+	gameHeader := fmt.Sprintf("Game %s", g.ID)
+	killsHeader := fmt.Sprintf("Kills: %d", len(g.KillSequence))
+
+	headerFormat := fmt.Sprintf("%%s%%%ds", 50-len(gameHeader))
+
+	header := fmt.Sprintf(headerFormat, gameHeader, killsHeader)
+
+	columns := fmt.Sprintf("  Time | %-50s", "Kill")
+	for _, p := range players {
+		columns += fmt.Sprintf(" | %s", p)
+	}
+
+	body := ""
+	for _, p := range Timeline(g) {
+		kill := fmt.Sprintf("%s killed %s", p.Killer, p.Dead)
+		if p.Means != "" {
+			kill += " by " + p.Means
+		}
+		body += fmt.Sprintf("\n%6s | %-50s", p.Time, kill)
+		for _, name := range players {
+			body += fmt.Sprintf(" | %*d", len(name), p.Scores[name])
+		}
+	}
+
+	return fmt.Sprintf("%s\n%s%s", header, columns, body)
+}
+
+// timelineHeader names the columns of the timeline csv, there is one row for every player after each kill
+var timelineHeader = []string{"game_id", "time", "killer", "dead", "means", "player", "score"}
+
+// TimelineCSV generates the timelines of the games as csv, one row for the score of every player
+// after each kill, so the score of a player over time is charted by filtering the player rows
+func TimelineCSV(gs []*parser.Game) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	rows := [][]string{timelineHeader}
+	for _, g := range gs {
+		players := TimelinePlayers(g)
+		for _, p := range Timeline(g) {
+			for _, name := range players {
+				rows = append(rows, []string{g.ID, p.Time, p.Killer, p.Dead, p.Means, name, strconv.Itoa(p.Scores[name])})
+			}
+		}
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("could not write the timeline: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ForTimelines generates the timelines of many games
+func ForTimelines(gs []*parser.Game) string {
+	var timelines []string
+	for _, g := range gs {
+		timelines = append(timelines, ForTimeline(g))
+	}
+	return strings.Join(timelines, "\n\n")
+}
@@ -0,0 +1,92 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+var timelineGame = &parser.Game{
+	ID:      "1",
+	Players: []string{"Isgalamido", "Mocinha"},
+	KillSequence: []*parser.Kill{
+		{Killer: "<world>", Dead: "Isgalamido", Means: "MOD_TRIGGER_HURT", Time: "0:05"},
+		{Killer: "Isgalamido", Dead: "Mocinha", Means: "MOD_RAILGUN", Time: "0:10"},
+		{Killer: "Mocinha", Dead: "Mocinha", Means: "MOD_ROCKET_SPLASH", Time: "0:12"},
+		{Killer: "Zeh", Dead: "Mocinha", Means: "MOD_RAILGUN", Time: "0:20", Friendly: true},
+	},
+}
+
+func TestTimelinePlayers(t *testing.T) {
+	out := []string{"Isgalamido", "Mocinha", "Zeh"}
+	if r := TimelinePlayers(timelineGame); !reflect.DeepEqual(r, out) {
+		t.Errorf("was expecting %v, but returns %v", out, r)
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	tt := []struct {
+		description string
+		in          *parser.Game
+		out         []*TimelinePoint
+	}{
+		{
+			description: "without kills",
+			in:          &parser.Game{ID: "1"},
+			out:         nil,
+		},
+		{
+			description: "the scores after every kill",
+			in:          timelineGame,
+			out: []*TimelinePoint{
+				{Time: "0:05", Killer: "<world>", Dead: "Isgalamido", Means: "MOD_TRIGGER_HURT", Scores: map[string]int{"Isgalamido": -1, "Mocinha": 0, "Zeh": 0}},
+				{Time: "0:10", Killer: "Isgalamido", Dead: "Mocinha", Means: "MOD_RAILGUN", Scores: map[string]int{"Isgalamido": 0, "Mocinha": 0, "Zeh": 0}},
+				{Time: "0:12", Killer: "Mocinha", Dead: "Mocinha", Means: "MOD_ROCKET_SPLASH", Scores: map[string]int{"Isgalamido": 0, "Mocinha": 0, "Zeh": 0}},
+				{Time: "0:20", Killer: "Zeh", Dead: "Mocinha", Means: "MOD_RAILGUN", Scores: map[string]int{"Isgalamido": 0, "Mocinha": 0, "Zeh": -1}},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			if r := Timeline(tc.in); !reflect.DeepEqual(r, tc.out) {
+				t.Errorf("was expecting %v, but returns %v", tc.out, r)
+			}
+		})
+	}
+}
+
+func TestForTimeline(t *testing.T) {
+	out := `Game 1                                    Kills: 4
+  Time | Kill                                               | Isgalamido | Mocinha | Zeh
+  0:05 | <world> killed Isgalamido by MOD_TRIGGER_HURT      |         -1 |       0 |   0
+  0:10 | Isgalamido killed Mocinha by MOD_RAILGUN           |          0 |       0 |   0
+  0:12 | Mocinha killed Mocinha by MOD_ROCKET_SPLASH        |          0 |       0 |   0
+  0:20 | Zeh killed Mocinha by MOD_RAILGUN                  |          0 |       0 |  -1`
+
+	if r := ForTimeline(timelineGame); r != out {
+		t.Errorf("was expecting\n%v\nbut returns\n%v", out, r)
+	}
+}
+
+func TestTimelineCSV(t *testing.T) {
+	g := &parser.Game{
+		ID:      "2",
+		Players: []string{"Isgalamido", "Mocinha"},
+		KillSequence: []*parser.Kill{
+			{Killer: "Isgalamido", Dead: "Mocinha", Means: "MOD_RAILGUN", Time: "0:10"},
+		},
+	}
+	out := "game_id,time,killer,dead,means,player,score\n" +
+		"2,0:10,Isgalamido,Mocinha,MOD_RAILGUN,Isgalamido,1\n" +
+		"2,0:10,Isgalamido,Mocinha,MOD_RAILGUN,Mocinha,0\n"
+
+	r, err := TimelineCSV([]*parser.Game{g, {ID: "3"}})
+	if err != nil {
+		t.Fatalf("an unexpected error occurred: %v", err)
+	}
+	if string(r) != out {
+		t.Errorf("was expecting\n%v\nbut returns\n%v", out, string(r))
+	}
+}
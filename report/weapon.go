@@ -0,0 +1,203 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// Weapon represents the kills by a means of death and its share of all the kills by players
+type Weapon struct {
+	Name  string  `json:"name"`
+	Kills int     `json:"kills"`
+	Share float64 `json:"share"`
+}
+
+// NewWeapon creates a new Weapon instance
+func NewWeapon(name string) *Weapon {
+	return &Weapon{
+		Name: name,
+	}
+}
+
+// PlayerWeapons represents the kills and deaths of a player by every means of death,
+// the favourite weapon is the one the player killed more with
+type PlayerWeapons struct {
+	Name      string         `json:"name"`
+	Favourite string         `json:"favourite,omitempty"`
+	Kills     map[string]int `json:"kills"`
+	Deaths    map[string]int `json:"deaths"`
+}
+
+// NewPlayerWeapons creates a new PlayerWeapons instance
+func NewPlayerWeapons(name string) *PlayerWeapons {
+	return &PlayerWeapons{
+		Name:   name,
+		Kills:  map[string]int{},
+		Deaths: map[string]int{},
+	}
+}
+
+// favourite chooses the weapon with more kills, the ties are chosen by name
+func (p *PlayerWeapons) favourite() string {
+	favourite := ""
+	for w, k := range p.Kills {
+		if favourite == "" || k > p.Kills[favourite] || (k == p.Kills[favourite] && w < favourite) {
+			favourite = w
+		}
+	}
+	return favourite
+}
+
+// WeaponRanking accumulates the weapon stats of the players, the kills by the
+// <world> are deaths of the players but are not counted for any weapon
+type WeaponRanking struct {
+	TotalKills int
+	Weapons    map[string]*Weapon
+	Players    map[string]*PlayerWeapons
+}
+
+// NewWeaponRanking creates a new WeaponRanking instance
+func NewWeaponRanking() *WeaponRanking {
+	return &WeaponRanking{
+		TotalKills: 0,
+		Weapons:    map[string]*Weapon{},
+		Players:    map[string]*PlayerWeapons{},
+	}
+}
+
+// AddGame integrate the weapon stats of a game to the ranking
+func (r *WeaponRanking) AddGame(g *parser.Game) {
+	for name, s := range g.Weapons {
+		if _, ok := r.Players[name]; !ok {
+			r.Players[name] = NewPlayerWeapons(name)
+		}
+		p := r.Players[name]
+
+		for w, k := range s.Kills {
+			if _, ok := r.Weapons[w]; !ok {
+				r.Weapons[w] = NewWeapon(w)
+			}
+			r.Weapons[w].Kills += k
+			r.TotalKills += k
+			p.Kills[w] += k
+		}
+		for w, d := range s.Deaths {
+			p.Deaths[w] += d
+		}
+		p.Favourite = p.favourite()
+	}
+
+	r.shares()
+}
+
+// SetTotalKills replaces the total kills of the ranking, like with the kills of the players
+// left out of the ranked games, the shares of the weapons are of the new total
+func (r *WeaponRanking) SetTotalKills(n int) {
+	r.TotalKills = n
+	r.shares()
+}
+
+func (r *WeaponRanking) shares() {
+	for _, w := range r.Weapons {
+		w.Share = float64(w.Kills) / float64(r.TotalKills)
+	}
+}
+
+// WeaponKills counts the kills by players of the games, the total of a weapon ranking
+func WeaponKills(gs []*parser.Game) int {
+	n := 0
+	for _, g := range gs {
+		for _, s := range g.Weapons {
+			n += sum(s.Kills)
+		}
+	}
+	return n
+}
+
+// Ordered returns the weapons ordered by kills
+func (r *WeaponRanking) Ordered() []*Weapon {
+	var ws []*Weapon
+	for _, w := range r.Weapons {
+		ws = append(ws, w)
+	}
+
+	sort.Slice(ws, func(i, j int) bool {
+		return ws[i].Kills > ws[j].Kills || (ws[i].Kills == ws[j].Kills && ws[i].Name < ws[j].Name)
+	})
+
+	return ws
+}
+
+// OrderedPlayers returns the players ordered by name
+func (r *WeaponRanking) OrderedPlayers() []*PlayerWeapons {
+	var ps []*PlayerWeapons
+	for _, p := range r.Players {
+		ps = append(ps, p)
+	}
+
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i].Name < ps[j].Name
+	})
+
+	return ps
+}
+
+func sum(m map[string]int) int {
+	n := 0
+	for _, v := range m {
+		n += v
+	}
+	return n
+}
+
+// Report generates a text for the weapons, followed by the players stats
+func (r *WeaponRanking) Report() string {
+	header := `Position | Weapon                         | Kills | Share`
+	body := ""
+	for i, w := range r.Ordered() {
+		namePadLeft := strings.Repeat(" ", int(math.Max(0, float64(30-len(w.Name)))))
+		body += fmt.Sprintf("%8d | %s%s | %5d | %.1f%%\n", i+1, w.Name, namePadLeft, w.Kills, w.Share*100)
+	}
+
+	playersHeader := `Player                         | Favourite                      | Kills | Deaths`
+	playersBody := ""
+	for _, p := range r.OrderedPlayers() {
+		namePadLeft := strings.Repeat(" ", int(math.Max(0, float64(30-len(p.Name)))))
+		favouritePadLeft := strings.Repeat(" ", int(math.Max(0, float64(30-len(p.Favourite)))))
+		playersBody += fmt.Sprintf("%s%s | %s%s | %5d | %d\n", p.Name, namePadLeft, p.Favourite, favouritePadLeft, sum(p.Kills), sum(p.Deaths))
+	}
+
+	body = strings.TrimRight(body, "\n")
+	playersBody = strings.TrimRight(playersBody, "\n")
+	return fmt.Sprintf("%s\n%s\n\n%s\n%s", header, body, playersHeader, playersBody)
+}
+
+// ForWeapons generates a weapon ranking for many games
+func ForWeapons(gs []*parser.Game) string {
+	return ForWeaponsWithTotal(gs, WeaponKills(gs))
+}
+
+// ForWeaponsWithTotal generates a weapon ranking for many games with other total kills,
+// like the games without the excluded players ranked against the kills of every player
+func ForWeaponsWithTotal(gs []*parser.Game, totalKills int) string {
+	r := NewWeaponRanking()
+	for _, g := range gs {
+		r.AddGame(g)
+	}
+	r.SetTotalKills(totalKills)
+
+	gameHeader := fmt.Sprintf("Weapon Ranking")
+	totalKillsHeader := fmt.Sprintf("Total Kills: %d", r.TotalKills)
+
+	headerFormat := fmt.Sprintf("%%s%%%ds", 50-len(gameHeader))
+
+	header := fmt.Sprintf(headerFormat, gameHeader, totalKillsHeader)
+
+	body := r.Report()
+
+	return fmt.Sprintf("%s\n%s", header, body)
+}
@@ -0,0 +1,142 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+var weaponGames = []*parser.Game{
+	{
+		ID:         "1",
+		TotalKills: 4,
+		Players:    []string{"player one", "player two"},
+		Kills:      map[string]int{"player one": 2, "player two": 0},
+		Weapons: map[string]*parser.WeaponStats{
+			"player one": {
+				Kills:  map[string]int{"MOD_ROCKET": 1, "MOD_RAILGUN": 1},
+				Deaths: map[string]int{"MOD_TRIGGER_HURT": 1},
+			},
+			"player two": {
+				Kills:  map[string]int{"MOD_ROCKET": 1},
+				Deaths: map[string]int{"MOD_ROCKET": 1, "MOD_RAILGUN": 1},
+			},
+		},
+	},
+	{
+		ID:         "2",
+		TotalKills: 1,
+		Players:    []string{"player one", "player two"},
+		Kills:      map[string]int{"player one": 1, "player two": 0},
+		Weapons: map[string]*parser.WeaponStats{
+			"player one": {Kills: map[string]int{"MOD_RAILGUN": 1}},
+			"player two": {Deaths: map[string]int{"MOD_RAILGUN": 1}},
+		},
+	},
+	{
+		ID:         "3",
+		TotalKills: 0,
+		Players:    []string{},
+		Kills:      map[string]int{},
+	},
+}
+
+func TestWeaponRankingAddGame(t *testing.T) {
+	r := NewWeaponRanking()
+	for _, g := range weaponGames {
+		r.AddGame(g)
+	}
+
+	out := &WeaponRanking{
+		TotalKills: 4,
+		Weapons: map[string]*Weapon{
+			"MOD_ROCKET":  {Name: "MOD_ROCKET", Kills: 2, Share: 0.5},
+			"MOD_RAILGUN": {Name: "MOD_RAILGUN", Kills: 2, Share: 0.5},
+		},
+		Players: map[string]*PlayerWeapons{
+			"player one": {
+				Name:      "player one",
+				Favourite: "MOD_RAILGUN",
+				Kills:     map[string]int{"MOD_ROCKET": 1, "MOD_RAILGUN": 2},
+				Deaths:    map[string]int{"MOD_TRIGGER_HURT": 1},
+			},
+			"player two": {
+				Name:      "player two",
+				Favourite: "MOD_ROCKET",
+				Kills:     map[string]int{"MOD_ROCKET": 1},
+				Deaths:    map[string]int{"MOD_ROCKET": 1, "MOD_RAILGUN": 2},
+			},
+		},
+	}
+	if !reflect.DeepEqual(r, out) {
+		t.Errorf("was expecting %v, but returns %v", out, r)
+	}
+}
+
+func TestPlayerWeaponsFavourite(t *testing.T) {
+	tt := []struct {
+		description string
+		in          map[string]int
+		out         string
+	}{
+		{
+			description: "without kills",
+			in:          map[string]int{},
+			out:         "",
+		},
+		{
+			description: "the weapon with more kills",
+			in:          map[string]int{"MOD_ROCKET": 3, "MOD_RAILGUN": 5},
+			out:         "MOD_RAILGUN",
+		},
+		{
+			description: "a tie chosen by name",
+			in:          map[string]int{"MOD_SHOTGUN": 2, "MOD_MACHINEGUN": 2},
+			out:         "MOD_MACHINEGUN",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			p := NewPlayerWeapons("player")
+			p.Kills = tc.in
+			if r := p.favourite(); r != tc.out {
+				t.Errorf("was expecting %v, but returns %v", tc.out, r)
+			}
+		})
+	}
+}
+
+func TestForWeapons(t *testing.T) {
+	out := `Weapon Ranking                      Total Kills: 4
+Position | Weapon                         | Kills | Share
+       1 | MOD_RAILGUN                    |     2 | 50.0%
+       2 | MOD_ROCKET                     |     2 | 50.0%
+
+Player                         | Favourite                      | Kills | Deaths
+player one                     | MOD_RAILGUN                    |     3 | 1
+player two                     | MOD_ROCKET                     |     1 | 3`
+
+	if r := ForWeapons(weaponGames); r != out {
+		t.Errorf("was expecting\n%v\nbut returns\n%v", out, r)
+	}
+}
+
+func TestForWeaponsWithTotal(t *testing.T) {
+	out := `Weapon Ranking                      Total Kills: 8
+Position | Weapon                         | Kills | Share
+       1 | MOD_RAILGUN                    |     2 | 25.0%
+       2 | MOD_ROCKET                     |     2 | 25.0%
+
+Player                         | Favourite                      | Kills | Deaths
+player one                     | MOD_RAILGUN                    |     3 | 1
+player two                     | MOD_ROCKET                     |     1 | 3`
+
+	if r := ForWeaponsWithTotal(weaponGames, 8); r != out {
+		t.Errorf("was expecting\n%v\nbut returns\n%v", out, r)
+	}
+	if n := WeaponKills(weaponGames); n != 4 {
+		t.Errorf("was expecting 4 kills, but returns %d", n)
+	}
+}
@@ -0,0 +1,193 @@
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+// Known schema versions, the legacy version is the bare map of game id to game
+const (
+	LegacyVersion = 1
+	Version       = 2
+)
+
+// Reusable errors
+var (
+	ErrMalformedDocument  = errors.New("could not deserialize the games document")
+	ErrUnsupportedVersion = errors.New("unsupported games document schema version")
+)
+
+// Source identifies a log used to generate the games
+type Source struct {
+	Label  string `json:"label,omitempty"`
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// Document wraps the parsed games with the information about how they were generated
+type Document struct {
+	SchemaVersion int            `json:"schema_version"`
+	GeneratedAt   time.Time      `json:"generated_at"`
+	Sources       []*Source      `json:"sources"`
+	Games         []*parser.Game `json:"games"`
+}
+
+// NewDocument creates a new Document instance in the current schema version
+func NewDocument(games []*parser.Game, sources ...*Source) *Document {
+	if games == nil {
+		games = []*parser.Game{}
+	}
+	if sources == nil {
+		sources = []*Source{}
+	}
+	return &Document{
+		SchemaVersion: Version,
+		GeneratedAt:   time.Now().UTC(),
+		Sources:       sources,
+		Games:         games,
+	}
+}
+
+// Checksum calculates the sha256 of the content, used to identify the source logs
+func Checksum(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Decode reads a games document in any known schema version,
+// legacy documents are returned with LegacyVersion and their games ordered by id
+func Decode(b []byte) (*Document, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, ErrMalformedDocument
+	}
+
+	// only the enveloped documents have the version, legacy ones are keyed by game id
+	if _, ok := fields["schema_version"]; !ok {
+		return decodeLegacy(fields)
+	}
+
+	var d *Document
+	if err := json.Unmarshal(b, &d); err != nil {
+		return nil, ErrMalformedDocument
+	}
+	if d.SchemaVersion < LegacyVersion || d.SchemaVersion > Version {
+		return nil, ErrUnsupportedVersion
+	}
+	if d.Games == nil {
+		d.Games = []*parser.Game{}
+	}
+	if d.Sources == nil {
+		d.Sources = []*Source{}
+	}
+
+	return d, nil
+}
+
+func decodeLegacy(fields map[string]json.RawMessage) (*Document, error) {
+	d := &Document{
+		SchemaVersion: LegacyVersion,
+		Sources:       []*Source{},
+		Games:         []*parser.Game{},
+	}
+
+	for _, raw := range fields {
+		var g *parser.Game
+		if err := json.Unmarshal(raw, &g); err != nil || g == nil {
+			return nil, ErrMalformedDocument
+		}
+		d.Games = append(d.Games, g)
+	}
+
+	// map keys have no order, so games are ordered by their numeric ids
+	sort.Slice(d.Games, func(i, j int) bool {
+		x, errX := strconv.Atoi(d.Games[i].ID)
+		y, errY := strconv.Atoi(d.Games[j].ID)
+		if errX != nil || errY != nil {
+			return d.Games[i].ID < d.Games[j].ID
+		}
+		return x < y
+	})
+
+	return d, nil
+}
+
+// AddGames appends the games to the document, the first game takes the place of the game
+// with the replaced id, when informed and found
+func (d *Document) AddGames(replaces string, games []*parser.Game) {
+	if replaces != "" && len(games) > 0 {
+		for i, g := range d.Games {
+			if g.ID == replaces {
+				d.Games[i] = games[0]
+				games = games[1:]
+				break
+			}
+		}
+	}
+	d.Games = append(d.Games, games...)
+}
+
+// SetSource adds the source to the document, replacing the source with the same path
+func (d *Document) SetSource(s *Source) {
+	for i, o := range d.Sources {
+		if o.Path == s.Path {
+			d.Sources[i] = s
+			return
+		}
+	}
+	d.Sources = append(d.Sources, s)
+}
+
+// Encode serializes the document in the current schema version
+func (d *Document) Encode() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// Upgrade converts the document to the current schema version
+func (d *Document) Upgrade() {
+	if d.SchemaVersion == Version {
+		return
+	}
+	d.SchemaVersion = Version
+	d.GeneratedAt = time.Now().UTC()
+}
+
+// ReadFile reads a games document from the file system
+func ReadFile(path string) (*Document, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(b)
+}
+
+// ReadGames reads the games of a document from the file system, in any known schema version,
+// the documents keep the games ordered by id
+func ReadGames(path string) ([]*parser.Game, error) {
+	d, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return d.Games, nil
+}
+
+// WriteFile writes the games document to the file system
+func WriteFile(path string, d *Document) error {
+	b, err := d.Encode()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
@@ -0,0 +1,248 @@
+package schema
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+var fixtureGames = []*parser.Game{
+	{
+		ID:         "1",
+		TotalKills: 0,
+		Players:    []string{},
+		Kills:      map[string]int{},
+	},
+	{
+		ID:         "2",
+		TotalKills: 11,
+		Players:    []string{"Isgalamido", "Mocinha"},
+		Kills: map[string]int{
+			"Isgalamido": -7,
+			"Mocinha":    0,
+		},
+	},
+	{
+		ID:         "3",
+		TotalKills: 4,
+		Players:    []string{"Isgalamido", "Mocinha", "Zeh", "Dono da Bola"},
+		Kills: map[string]int{
+			"Dono da Bola": -1,
+			"Isgalamido":   1,
+			"Mocinha":      0,
+			"Zeh":          -2,
+		},
+	},
+}
+
+func TestReadFile(t *testing.T) {
+	basePath, err := os.Getwd()
+	if err != nil {
+		t.Errorf("could not determine where the app is running: %v", err)
+	}
+	fixturesPath := path.Join(basePath, "..", "fixtures")
+
+	tt := []struct {
+		description string
+		in          string
+		out         *Document
+		err         error
+	}{
+		{
+			description: "read a legacy document",
+			in:          path.Join(fixturesPath, "games.json"),
+			out: &Document{
+				SchemaVersion: LegacyVersion,
+				Sources:       []*Source{},
+				Games:         fixtureGames,
+			},
+		},
+		{
+			description: "read a current document",
+			in:          path.Join(fixturesPath, "games_v2.json"),
+			out: &Document{
+				SchemaVersion: Version,
+				GeneratedAt:   time.Date(2020, 7, 20, 12, 0, 0, 0, time.UTC),
+				Sources: []*Source{
+					{
+						Path:   "./games.log",
+						SHA256: "6b7ea9e1d2d0e6c8f0b3d5b3c7e5b7a0f2d4f6e8a0c2e4f6a8b0c2d4e6f8a0b2",
+					},
+				},
+				Games: fixtureGames,
+			},
+		},
+		{
+			description: "read a malformed document",
+			in:          path.Join(fixturesPath, "games_malformed.json"),
+			err:         ErrMalformedDocument,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			d, err := ReadFile(tc.in)
+			if err != tc.err {
+				t.Errorf(`was expecting "%v" error, but returns "%v" error`, tc.err, err)
+			}
+			if !reflect.DeepEqual(d, tc.out) {
+				t.Errorf("was expecting\n%#v\nbut returns\n%#v\n", tc.out, d)
+			}
+		})
+	}
+}
+
+func TestReadGames(t *testing.T) {
+	basePath, err := os.Getwd()
+	if err != nil {
+		t.Errorf("could not determine where the app is running: %v", err)
+	}
+	fixturesPath := path.Join(basePath, "..", "fixtures")
+
+	tt := []struct {
+		description string
+		in          string
+		out         []*parser.Game
+		err         bool
+	}{
+		{
+			description: "read the games of a legacy document",
+			in:          path.Join(fixturesPath, "games.json"),
+			out:         fixtureGames,
+		},
+		{
+			description: "read the games of a current document",
+			in:          path.Join(fixturesPath, "games_v2.json"),
+			out:         fixtureGames,
+		},
+		{
+			description: "read a nonexistent document",
+			in:          path.Join(fixturesPath, "nonexistent.json"),
+			err:         true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			gs, err := ReadGames(tc.in)
+			if (err != nil) != tc.err {
+				t.Errorf("was expecting error %v, but returns %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(gs, tc.out) {
+				t.Errorf("was expecting\n%v\nbut returns\n%v\n", tc.out, gs)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tt := []struct {
+		description string
+		in          string
+		err         error
+	}{
+		{"an empty legacy document", `{}`, nil},
+		{"an empty current document", `{"schema_version": 2}`, nil},
+		{"a document from the future", `{"schema_version": 3, "games": []}`, ErrUnsupportedVersion},
+		{"a legacy document with invalid games", `{"1": 1}`, ErrMalformedDocument},
+		{"not a document", `[]`, ErrMalformedDocument},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			if _, err := Decode([]byte(tc.in)); err != tc.err {
+				t.Errorf(`was expecting "%v" error, but returns "%v" error`, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestDocumentEncode(t *testing.T) {
+	d := NewDocument(fixtureGames, &Source{Path: "games.log", SHA256: "abc"})
+	d.GeneratedAt = time.Date(2020, 7, 20, 12, 0, 0, 0, time.UTC)
+
+	b, err := d.Encode()
+	if err != nil {
+		t.Errorf("an unexpected error occurred: %v", err)
+	}
+
+	// an encoded document should be decoded without changes
+	r, err := Decode(b)
+	if err != nil {
+		t.Errorf("an unexpected error occurred: %v", err)
+	}
+	if !reflect.DeepEqual(r, d) {
+		t.Errorf("was expecting\n%#v\nbut returns\n%#v\n", d, r)
+	}
+}
+
+func TestDocumentUpgrade(t *testing.T) {
+	d, err := Decode([]byte(`{"2": {"id": "2"}, "10": {"id": "10"}}`))
+	if err != nil {
+		t.Errorf("an unexpected error occurred: %v", err)
+	}
+
+	d.Upgrade()
+
+	if d.SchemaVersion != Version {
+		t.Errorf("was expecting version %d, but returns %d", Version, d.SchemaVersion)
+	}
+	if d.GeneratedAt.IsZero() {
+		t.Errorf("was expecting the generation time to be filled")
+	}
+	if d.Games[0].ID != "2" || d.Games[1].ID != "10" {
+		t.Errorf("was expecting the games ordered by id, but returns %v and %v", d.Games[0].ID, d.Games[1].ID)
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	out := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if r, _ := Checksum(strings.NewReader("hello")); r != out {
+		t.Errorf("was expecting %s, but returns %s", out, r)
+	}
+}
+
+func TestDocumentAddGames(t *testing.T) {
+	tt := []struct {
+		description string
+		replaces    string
+		in          []*parser.Game
+		out         []string
+	}{
+		{"append games", "", []*parser.Game{{ID: "3"}, {ID: "4"}}, []string{"1", "2", "3", "4"}},
+		{"replace a game", "2", []*parser.Game{{ID: "2"}, {ID: "3"}}, []string{"1", "2", "3"}},
+		{"replace a game with other id", "1", []*parser.Game{{ID: "a"}}, []string{"a", "2"}},
+		{"replace a nonexistent game", "5", []*parser.Game{{ID: "5"}}, []string{"1", "2", "5"}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			d := NewDocument([]*parser.Game{{ID: "1"}, {ID: "2"}})
+			d.AddGames(tc.replaces, tc.in)
+
+			var ids []string
+			for _, g := range d.Games {
+				ids = append(ids, g.ID)
+			}
+			if !reflect.DeepEqual(ids, tc.out) {
+				t.Errorf("was expecting %v, but returns %v", tc.out, ids)
+			}
+		})
+	}
+}
+
+func TestDocumentSetSource(t *testing.T) {
+	d := NewDocument(nil, &Source{Path: "a.log", SHA256: "1"})
+	d.SetSource(&Source{Path: "b.log", SHA256: "2"})
+	d.SetSource(&Source{Path: "a.log", SHA256: "3"})
+
+	out := []*Source{{Path: "a.log", SHA256: "3"}, {Path: "b.log", SHA256: "2"}}
+	if !reflect.DeepEqual(d.Sources, out) {
+		t.Errorf("was expecting %v, but returns %v", out, d.Sources)
+	}
+}
@@ -0,0 +1,200 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bgildson/enext-challenge/parser"
+	"github.com/bgildson/enext-challenge/report"
+)
+
+// gameTypes names the game types in the pages
+var gameTypes = map[string]string{
+	parser.GameTypeFFA:        "free for all",
+	parser.GameTypeTournament: "tournament",
+	parser.GameTypeSingle:     "single player",
+	parser.GameTypeTDM:        "team deathmatch",
+	parser.GameTypeCTF:        "capture the flag",
+}
+
+// slug converts a name to be used in a file name, keeping only letters and digits
+func slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, c := range strings.ToLower(name) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	s := strings.TrimSuffix(b.String(), "-")
+	if s == "" {
+		s = "unnamed"
+	}
+	return s
+}
+
+// files assigns a unique file name to every name, with the prefix, the names with the same slug are numbered
+func files(prefix string, names []string) map[string]string {
+	fs := map[string]string{}
+	used := map[string]bool{}
+	for _, n := range names {
+		if _, ok := fs[n]; ok {
+			continue
+		}
+		base := prefix + slug(n)
+		f := base + ".html"
+		for i := 2; used[f]; i++ {
+			f = fmt.Sprintf("%s-%d.html", base, i)
+		}
+		used[f] = true
+		fs[n] = f
+	}
+	return fs
+}
+
+// playerGame represents a game played by a player
+type playerGame struct {
+	Game  *parser.Game
+	Kills int
+}
+
+// playerPage represents the page of a player
+type playerPage struct {
+	Name    string
+	Points  int
+	Games   []*playerGame
+	Weapons *report.PlayerWeapons
+}
+
+// gamePage represents the page of a game
+type gamePage struct {
+	Game    *parser.Game
+	Ranking *report.Ranking
+	Awards  []*report.Award
+}
+
+// Pages renders the pages of the match history, by their file names, the index.html lists
+// the games and the players, the ranking.html has the rankings, and every game and player has a page
+func Pages(games []*parser.Game) (map[string][]byte, error) {
+	general := report.NewRanking()
+	teams := report.NewTeamRanking()
+	weapons := report.NewWeaponRanking()
+
+	var ids, players []string
+	played := map[string][]*playerGame{}
+	for _, g := range games {
+		general.AddGame(g)
+		teams.AddGame(g)
+		weapons.AddGame(g)
+
+		ids = append(ids, g.ID)
+		for _, p := range g.Players {
+			if _, ok := played[p]; !ok {
+				players = append(players, p)
+			}
+			played[p] = append(played[p], &playerGame{Game: g, Kills: g.Kills[p]})
+		}
+	}
+	sort.Strings(players)
+
+	gameFiles := files("game-", ids)
+	playerFiles := files("player-", players)
+
+	t, err := template.New("site").Funcs(template.FuncMap{
+		"gameURL":   func(id string) string { return gameFiles[id] },
+		"playerURL": func(name string) string { return playerFiles[name] },
+		"gameType":  func(t string) string { return gameTypes[t] },
+		"awardName": report.AwardName,
+		"inc":       func(i int) int { return i + 1 },
+		"percent":   func(f float64) string { return fmt.Sprintf("%.1f%%", f*100) },
+		"weaponNames": func(p *report.PlayerWeapons) []string {
+			var ws []string
+			for w := range p.Kills {
+				ws = append(ws, w)
+			}
+			for w := range p.Deaths {
+				if _, ok := p.Kills[w]; !ok {
+					ws = append(ws, w)
+				}
+			}
+			sort.Strings(ws)
+			return ws
+		},
+	}).Parse(templates)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the templates: %v", err)
+	}
+
+	pages := map[string][]byte{}
+	render := func(file, name string, data interface{}) error {
+		var b bytes.Buffer
+		if err := t.ExecuteTemplate(&b, name, data); err != nil {
+			return fmt.Errorf("could not render %s: %v", file, err)
+		}
+		pages[file] = b.Bytes()
+		return nil
+	}
+
+	if err := render("index.html", "index", map[string]interface{}{"Games": games, "Players": players}); err != nil {
+		return nil, err
+	}
+	if err := render("ranking.html", "ranking", map[string]interface{}{"General": general, "Teams": teams, "Weapons": weapons}); err != nil {
+		return nil, err
+	}
+
+	for _, g := range games {
+		r := report.NewRanking()
+		r.AddGame(g)
+		page := &gamePage{
+			Game:    g,
+			Ranking: r,
+			Awards:  report.Achievements(g, report.DefaultAchievementConfig),
+		}
+		if err := render(gameFiles[g.ID], "game", page); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, p := range players {
+		page := &playerPage{
+			Name:    p,
+			Points:  general.Players[p].Points,
+			Games:   played[p],
+			Weapons: weapons.Players[p],
+		}
+		if err := render(playerFiles[p], "playerPage", page); err != nil {
+			return nil, err
+		}
+	}
+
+	return pages, nil
+}
+
+// Write renders the pages of the match history to the directory, creating it when needed
+func Write(dir string, games []*parser.Game) error {
+	pages, err := Pages(games)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("could not create the site directory: %v", err)
+	}
+	for file, b := range pages {
+		if err := ioutil.WriteFile(filepath.Join(dir, file), b, 0644); err != nil {
+			return fmt.Errorf("could not write %s: %v", file, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package site
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/bgildson/enext-challenge/parser"
+)
+
+var games = []*parser.Game{
+	{
+		ID:         "1",
+		TotalKills: 3,
+		Players:    []string{"Isgalamido", "Dono da Bola"},
+		Kills:      map[string]int{"Isgalamido": 1, "Dono da Bola": 1},
+		Weapons: map[string]*parser.WeaponStats{
+			"Isgalamido":   {Kills: map[string]int{"MOD_RAILGUN": 2}, Deaths: map[string]int{"MOD_TRIGGER_HURT": 1}},
+			"Dono da Bola": {Deaths: map[string]int{"MOD_RAILGUN": 2}},
+		},
+		KillSequence: []*parser.Kill{
+			{Killer: "<world>", Dead: "Isgalamido", Means: "MOD_TRIGGER_HURT", Time: "0:05"},
+			{Killer: "Isgalamido", Dead: "Dono da Bola", Means: "MOD_RAILGUN", Time: "0:10"},
+			{Killer: "Isgalamido", Dead: "Dono da Bola", Means: "MOD_RAILGUN", Time: "0:12"},
+		},
+	},
+	{
+		ID:         "2",
+		GameType:   parser.GameTypeCTF,
+		TotalKills: 1,
+		Players:    []string{"<Zeh>", "Dono da Bola"},
+		Kills:      map[string]int{"<Zeh>": 1, "Dono da Bola": 0},
+		CTF:        map[string]*parser.CTFStats{"<Zeh>": {Pickups: 1, Captures: 1}},
+	},
+}
+
+func TestSlug(t *testing.T) {
+	tt := []struct {
+		in  string
+		out string
+	}{
+		{"Isgalamido", "isgalamido"},
+		{"Dono da Bola", "dono-da-bola"},
+		{"<Zeh>", "zeh"},
+		{"server1-3", "server1-3"},
+		{"***", "unnamed"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.in, func(t *testing.T) {
+			if r := slug(tc.in); r != tc.out {
+				t.Errorf("was expecting %v, but returns %v", tc.out, r)
+			}
+		})
+	}
+}
+
+func TestFiles(t *testing.T) {
+	out := map[string]string{
+		"Zeh":   "player-zeh.html",
+		"<Zeh>": "player-zeh-2.html",
+		"zeh!":  "player-zeh-3.html",
+	}
+	if r := files("player-", []string{"Zeh", "<Zeh>", "Zeh", "zeh!"}); !reflect.DeepEqual(r, out) {
+		t.Errorf("was expecting %v, but returns %v", out, r)
+	}
+}
+
+func TestPages(t *testing.T) {
+	pages, err := Pages(games)
+	if err != nil {
+		t.Fatalf("an unexpected error occurred: %v", err)
+	}
+
+	var names []string
+	for n := range pages {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	out := []string{"game-1.html", "game-2.html", "index.html", "player-dono-da-bola.html", "player-isgalamido.html", "player-zeh.html", "ranking.html"}
+	if !reflect.DeepEqual(names, out) {
+		t.Errorf("was expecting %v, but returns %v", out, names)
+	}
+
+	tt := []struct {
+		page     string
+		contains []string
+	}{
+		{
+			page:     "index.html",
+			contains: []string{`<a href="game-2.html">Game 2</a>`, `<td>capture the flag</td>`, `<a href="player-zeh.html">&lt;Zeh&gt;</a>`},
+		},
+		{
+			page:     "ranking.html",
+			contains: []string{`<td><a href="player-isgalamido.html">Isgalamido</a></td><td class="number">1</td>`, `<td>MOD_RAILGUN</td><td class="number">2</td><td class="number">100.0%</td>`},
+		},
+		{
+			page:     "game-1.html",
+			contains: []string{`<h1>Game 1</h1>`, `<td>first blood</td>`, `<td>&lt;world&gt;</td><td><a href="player-isgalamido.html">Isgalamido</a></td>`},
+		},
+		{
+			page:     "game-2.html",
+			contains: []string{`<h2>Capture the Flag</h2>`, `<td><a href="player-zeh.html">&lt;Zeh&gt;</a></td><td class="number">1</td><td class="number">1</td>`},
+		},
+		{
+			page:     "player-dono-da-bola.html",
+			contains: []string{`<h1>Dono da Bola</h1>`, `<a href="game-1.html">Game 1</a>`, `<a href="game-2.html">Game 2</a>`, `<td>MOD_RAILGUN</td><td class="number">0</td><td class="number">2</td>`},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.page, func(t *testing.T) {
+			page := string(pages[tc.page])
+			for _, c := range tc.contains {
+				if !strings.Contains(page, c) {
+					t.Errorf("was expecting\n%v\nin\n%v", c, page)
+				}
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "site")
+	if err != nil {
+		t.Fatalf("could not create a temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	out := filepath.Join(dir, "public")
+	if err := Write(out, games); err != nil {
+		t.Fatalf("an unexpected error occurred: %v", err)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(out, "index.html"))
+	if err != nil {
+		t.Fatalf("was expecting the index page, but returns %v", err)
+	}
+	if !strings.Contains(string(b), "<h1>Match History</h1>") {
+		t.Errorf("was expecting the index page, but returns\n%s", b)
+	}
+}
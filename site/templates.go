@@ -0,0 +1,104 @@
+package site
+
+// templates are the pages of the site, every page uses the layout and the player helper,
+// which links the player page, or only writes the name for the <world> and unknown players
+const templates = `
+{{define "header"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.}} - Match History</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 60em; color: #222222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border-bottom: 1px solid #dddddd; padding: 0.25em 0.75em; text-align: left; }
+td.number { text-align: right; }
+nav a { margin-right: 1em; }
+</style>
+</head>
+<body>
+<nav><a href="index.html">Games</a><a href="ranking.html">Rankings</a></nav>
+<h1>{{.}}</h1>
+{{end}}
+
+{{define "footer"}}</body>
+</html>
+{{end}}
+
+{{define "player"}}{{with playerURL .}}<a href="{{.}}">{{end}}{{.}}{{if playerURL .}}</a>{{end}}{{end}}
+
+{{define "index"}}{{template "header" "Match History"}}
+<h2>Games</h2>
+<table>
+<tr><th>Game</th><th>Server</th><th>Type</th><th>Total Kills</th><th>Players</th></tr>
+{{range .Games}}<tr><td><a href="{{gameURL .ID}}">Game {{.ID}}</a></td><td>{{.Server}}</td><td>{{gameType .GameType}}</td><td class="number">{{.TotalKills}}</td><td class="number">{{len .Players}}</td></tr>
+{{end}}</table>
+<h2>Players</h2>
+<ul>
+{{range .Players}}<li>{{template "player" .}}</li>
+{{end}}</ul>
+{{template "footer"}}{{end}}
+
+{{define "ranking"}}{{template "header" "Rankings"}}
+<h2>General Ranking</h2>
+<p>Total Kills: {{.General.TotalKills}}</p>
+<table>
+<tr><th>Position</th><th>Player</th><th>Points</th></tr>
+{{range $i, $p := .General.Ordered}}<tr><td class="number">{{inc $i}}</td><td>{{template "player" $p.Name}}</td><td class="number">{{$p.Points}}</td></tr>
+{{end}}</table>
+{{if .Teams.Games}}<h2>Team Ranking</h2>
+<p>Team Games: {{.Teams.Games}}</p>
+<table>
+<tr><th>Position</th><th>Team</th><th>Wins</th><th>Score</th><th>Kills</th><th>Friendly Fire</th></tr>
+{{range $i, $t := .Teams.Ordered}}<tr><td class="number">{{inc $i}}</td><td>{{$t.Name}}</td><td class="number">{{$t.Wins}}</td><td class="number">{{$t.Score}}</td><td class="number">{{$t.Kills}}</td><td class="number">{{$t.FriendlyFire}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Weapons.TotalKills}}<h2>Weapon Ranking</h2>
+<table>
+<tr><th>Position</th><th>Weapon</th><th>Kills</th><th>Share</th></tr>
+{{range $i, $w := .Weapons.Ordered}}<tr><td class="number">{{inc $i}}</td><td>{{$w.Name}}</td><td class="number">{{$w.Kills}}</td><td class="number">{{percent $w.Share}}</td></tr>
+{{end}}</table>
+{{end}}{{template "footer"}}{{end}}
+
+{{define "game"}}{{template "header" (printf "Game %s" .Game.ID)}}
+<p>{{with .Game.Server}}Server: {{.}} | {{end}}Type: {{gameType .Game.GameType}} | Total Kills: {{.Game.TotalKills}}</p>
+<h2>Players</h2>
+<table>
+<tr><th>Position</th><th>Player</th><th>Points</th></tr>
+{{range $i, $p := .Ranking.Ordered}}<tr><td class="number">{{inc $i}}</td><td>{{template "player" $p.Name}}</td><td class="number">{{$p.Points}}</td></tr>
+{{end}}</table>
+{{with .Game.TeamScores}}<h2>Team Scores</h2>
+<table>
+<tr><th>Team</th><th>Score</th></tr>
+{{range $team, $score := .}}<tr><td>{{$team}}</td><td class="number">{{$score}}</td></tr>
+{{end}}</table>
+{{end}}{{with .Game.CTF}}<h2>Capture the Flag</h2>
+<table>
+<tr><th>Player</th><th>Pickups</th><th>Captures</th><th>Returns</th><th>Carrier Kills</th></tr>
+{{range $name, $s := .}}<tr><td>{{template "player" $name}}</td><td class="number">{{$s.Pickups}}</td><td class="number">{{$s.Captures}}</td><td class="number">{{$s.Returns}}</td><td class="number">{{$s.CarrierKills}}</td></tr>
+{{end}}</table>
+{{end}}{{with .Awards}}<h2>Awards</h2>
+<table>
+<tr><th>Time</th><th>Award</th><th>Player</th><th>Kills</th></tr>
+{{range .}}<tr><td>{{.Time}}</td><td>{{awardName .Type}}</td><td>{{template "player" .Player}}</td><td class="number">{{.Kills}}</td></tr>
+{{end}}</table>
+{{end}}{{with .Game.KillSequence}}<h2>Timeline</h2>
+<table>
+<tr><th>Time</th><th>Killer</th><th>Dead</th><th>Means</th></tr>
+{{range .}}<tr><td>{{.Time}}</td><td>{{template "player" .Killer}}</td><td>{{template "player" .Dead}}</td><td>{{.Means}}</td></tr>
+{{end}}</table>
+{{end}}{{template "footer"}}{{end}}
+
+{{define "playerPage"}}{{template "header" .Name}}
+<p>Points: {{.Points}}{{with .Weapons}}{{with .Favourite}} | Favourite Weapon: {{.}}{{end}}{{end}}</p>
+<h2>Games</h2>
+<table>
+<tr><th>Game</th><th>Type</th><th>Kills</th></tr>
+{{range .Games}}<tr><td><a href="{{gameURL .Game.ID}}">Game {{.Game.ID}}</a></td><td>{{gameType .Game.GameType}}</td><td class="number">{{.Kills}}</td></tr>
+{{end}}</table>
+{{with .Weapons}}<h2>Weapons</h2>
+<table>
+<tr><th>Weapon</th><th>Kills</th><th>Deaths</th></tr>
+{{range weaponNames .}}<tr><td>{{.}}</td><td class="number">{{index $.Weapons.Kills .}}</td><td class="number">{{index $.Weapons.Deaths .}}</td></tr>
+{{end}}</table>
+{{end}}{{template "footer"}}{{end}}
+`